@@ -0,0 +1,341 @@
+package attackpath
+
+import (
+	"fmt"
+	"strings"
+
+	management "github.com/f0rk3b0mb/GoCloudGhost/azure/enum"
+	"github.com/f0rk3b0mb/GoCloudGhost/azure/graph"
+	"github.com/f0rk3b0mb/GoCloudGhost/azure/models"
+	"github.com/f0rk3b0mb/GoCloudGhost/azure/rbac"
+	"github.com/f0rk3b0mb/GoCloudGhost/session"
+)
+
+// Directory role template IDs whose holders can escalate to Global Administrator
+const (
+	privilegedRoleAdministratorTemplateID = "e8611ab8-c189-46e8-94e1-60213ab1f814"
+	privilegedAuthAdministratorTemplateID = "7be44c8a-adaf-4e2a-84d6-ab2649e08a13"
+	applicationAdministratorTemplateID    = "9b895d92-2cd3-44c7-9d02-a6ac2d5ea5c3"
+	cloudAppAdministratorTemplateID       = "158c047a-c907-4556-b7ef-446551a6b5f7"
+)
+
+// grantingAppPermissions are application permissions that let a service principal
+// assign itself any directory role
+var grantingAppPermissions = map[string]bool{
+	"RoleManagement.ReadWrite.Directory": true,
+	"AppRoleAssignment.ReadWrite.All":    true,
+}
+
+// roleAssignmentWrite is the operation that makes a role an owner-level role
+const roleAssignmentWrite = "Microsoft.Authorization/roleAssignments/write"
+
+// LoadAzure adds everything the azure commands saved to the session to the graph.
+// Missing session entries are skipped, the graph holds whatever has been collected.
+// It returns the names of the session entries that were loaded.
+func LoadAzure(g *Graph, engine *rbac.Engine) ([]string, error) {
+	directory, err := LoadDirectory(g)
+	if err != nil {
+		return nil, err
+	}
+
+	assignments, err := LoadRoleAssignments(g, engine)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(directory, assignments...), nil
+}
+
+// LoadDirectory adds group memberships, app ownership, application permissions and
+// directory roles collected by the graph commands
+func LoadDirectory(g *Graph) ([]string, error) {
+	var loader sessionLoader
+
+	var groups []models.GraphGroup
+	if err := loader.load(graph.GroupsSession, &groups); err != nil {
+		return nil, err
+	}
+	addGroups(g, groups)
+
+	var audits []models.AppAudit
+	if err := loader.load(graph.AppAuditSession, &audits); err != nil {
+		return nil, err
+	}
+	addApplications(g, audits)
+
+	var directoryRoles []models.DirectoryRoleAssignment
+	if err := loader.load(graph.DirectoryRolesSession, &directoryRoles); err != nil {
+		return nil, err
+	}
+	addDirectoryRoles(g, directoryRoles, audits)
+
+	return loader.loaded, nil
+}
+
+// LoadRoleAssignments adds Azure RBAC role assignments together with the scope
+// hierarchy and resources collected by the management command
+func LoadRoleAssignments(g *Graph, engine *rbac.Engine) ([]string, error) {
+	var loader sessionLoader
+
+	var root models.ManagementGroupChild
+	if err := loader.load(management.ManagementGroupsSession, &root); err != nil {
+		return nil, err
+	}
+	if root.ID != "" {
+		addManagementGroupTree(g, root)
+	}
+
+	var resources []models.Resource
+	if err := loader.load(management.ResourcesSession, &resources); err != nil {
+		return nil, err
+	}
+	for _, resource := range resources {
+		addScope(g, resource.ID, resource.Name, map[string]string{"type": resource.Type})
+	}
+
+	var identities []models.IdentityAttachment
+	if err := loader.load(management.IdentitiesSession, &identities); err != nil {
+		return nil, err
+	}
+	addIdentities(g, identities)
+
+	var definitions []models.RoleDefinition
+	if err := loader.load(management.RoleDefinitionsSession, &definitions); err != nil {
+		return nil, err
+	}
+
+	var assignments []models.ResolvedRoleAssignment
+	if err := loader.load(management.RoleAssignmentsSession, &assignments); err != nil {
+		return nil, err
+	}
+	addRoleAssignments(g, assignments, definitions, engine)
+
+	// Assignments at the root scope are inherited by the tenant root management group
+	if root.ID != "" {
+		g.AddEdge("/", root.ID, EdgeContains, nil)
+	}
+
+	return loader.loaded, nil
+}
+
+// sessionLoader loads session entries and remembers which ones existed
+type sessionLoader struct {
+	loaded []string
+}
+
+func (l *sessionLoader) load(name string, v interface{}) error {
+	found, err := session.Load(name, v)
+	if found {
+		l.loaded = append(l.loaded, name)
+	}
+	return err
+}
+
+func addPrincipal(g *Graph, principal models.Principal) *Node {
+	properties := map[string]string{}
+	if principal.Identifier != "" {
+		properties["identifier"] = principal.Identifier
+	}
+	return g.AddNode(principal.ID, principal.Type, principal.DisplayName, properties)
+}
+
+func addGroups(g *Graph, groups []models.GraphGroup) {
+	for _, group := range groups {
+		g.AddNode(group.ID, KindGroup, group.DisplayName, map[string]string{
+			"roleAssignable": fmt.Sprint(group.IsAssignableToRole),
+		})
+
+		for _, member := range group.Members {
+			addPrincipal(g, member)
+			g.AddEdge(member.ID, group.ID, EdgeMemberOf, nil)
+		}
+	}
+}
+
+func addApplications(g *Graph, audits []models.AppAudit) {
+	servicePrincipals := make(map[string]string)
+
+	for _, audit := range audits {
+		kind := KindApplication
+		if audit.Kind == models.AppAuditKindServicePrincipal {
+			kind = KindServicePrincipal
+			if audit.ServicePrincipalType == "ManagedIdentity" {
+				kind = KindManagedIdentity
+			}
+			servicePrincipals[audit.AppID] = audit.ObjectID
+		}
+
+		g.AddNode(audit.ObjectID, kind, audit.DisplayName, map[string]string{"identifier": audit.AppID})
+
+		for _, owner := range audit.Owners {
+			addPrincipal(g, owner)
+			g.AddEdge(owner.ID, audit.ObjectID, EdgeOwns, nil)
+		}
+	}
+
+	for _, audit := range audits {
+		if audit.Kind == models.AppAuditKindApplication {
+			g.AddEdge(audit.ObjectID, servicePrincipals[audit.AppID], EdgeAuthenticatesAs, nil)
+			continue
+		}
+
+		for _, permission := range audit.AppPermissions {
+			if !grantingAppPermissions[permission[strings.LastIndex(permission, "/")+1:]] {
+				continue
+			}
+			globalAdmin := addDirectoryRole(g, graph.GlobalAdministratorTemplateID, "Global Administrator")
+			g.AddEdge(audit.ObjectID, globalAdmin.ID, EdgeCanGrant, map[string]string{"permission": permission})
+		}
+	}
+}
+
+func addDirectoryRole(g *Graph, templateID, name string) *Node {
+	return g.AddNode("directoryrole:"+templateID, KindDirectoryRole, name, map[string]string{"templateId": templateID})
+}
+
+func addDirectoryRoles(g *Graph, assignments []models.DirectoryRoleAssignment, audits []models.AppAudit) {
+	for _, assignment := range assignments {
+		// Administrative unit scoped roles only apply to the unit's members
+		if assignment.Scope != "/" {
+			continue
+		}
+
+		role := addDirectoryRole(g, assignment.RoleTemplateID, assignment.RoleName)
+		addPrincipal(g, assignment.Principal)
+		g.AddEdge(assignment.Principal.ID, role.ID, EdgeHasDirectoryRole, nil)
+	}
+
+	globalAdmin := addDirectoryRole(g, graph.GlobalAdministratorTemplateID, "Global Administrator")
+
+	if role := g.Node("directoryrole:" + privilegedRoleAdministratorTemplateID); role != nil {
+		g.AddEdge(role.ID, globalAdmin.ID, EdgeCanGrant, nil)
+	}
+	if role := g.Node("directoryrole:" + privilegedAuthAdministratorTemplateID); role != nil {
+		g.AddEdge(role.ID, globalAdmin.ID, EdgeResetPassword, nil)
+	}
+
+	// Application administrators can add credentials to any application or service principal
+	for _, templateID := range []string{applicationAdministratorTemplateID, cloudAppAdministratorTemplateID} {
+		role := g.Node("directoryrole:" + templateID)
+		if role == nil {
+			continue
+		}
+		for _, audit := range audits {
+			g.AddEdge(role.ID, audit.ObjectID, EdgeAddSecret, nil)
+		}
+	}
+}
+
+func addManagementGroupTree(g *Graph, node models.ManagementGroupChild) {
+	kind := KindManagementGroup
+	id := node.ID
+	if strings.EqualFold(node.Type, "/subscriptions") {
+		kind = KindSubscription
+		id = "/subscriptions/" + node.Name
+	}
+
+	g.AddNode(id, kind, node.DisplayName, nil)
+
+	for _, child := range node.Children {
+		addManagementGroupTree(g, child)
+
+		childID := child.ID
+		if strings.EqualFold(child.Type, "/subscriptions") {
+			childID = "/subscriptions/" + child.Name
+		}
+		g.AddEdge(id, childID, EdgeContains, nil)
+	}
+}
+
+// addScope adds an Azure scope together with the resource group and subscription above it
+func addScope(g *Graph, scope, name string, properties map[string]string) *Node {
+	segments := strings.Split(strings.Trim(scope, "/"), "/")
+	lower := strings.ToLower(scope)
+
+	switch {
+	case scope == "/":
+		return g.AddNode(scope, KindManagementGroup, "Tenant Root", properties)
+	case strings.HasPrefix(lower, "/providers/microsoft.management/managementgroups/"):
+		if name == "" {
+			name = segments[len(segments)-1]
+		}
+		return g.AddNode(scope, KindManagementGroup, name, properties)
+	case !strings.HasPrefix(lower, "/subscriptions/") || len(segments) < 2:
+		return g.AddNode(scope, KindResource, name, properties)
+	case len(segments) == 2:
+		return g.AddNode(scope, KindSubscription, name, properties)
+	}
+
+	subscription := addScope(g, "/"+strings.Join(segments[:2], "/"), "", nil)
+
+	if len(segments) <= 4 {
+		if name == "" && len(segments) == 4 {
+			name = segments[3]
+		}
+		group := g.AddNode(scope, KindResourceGroup, name, properties)
+		g.AddEdge(subscription.ID, group.ID, EdgeContains, nil)
+		return group
+	}
+
+	group := addScope(g, "/"+strings.Join(segments[:4], "/"), "", nil)
+	if name == "" {
+		name = segments[len(segments)-1]
+	}
+	resource := g.AddNode(scope, KindResource, name, properties)
+	g.AddEdge(group.ID, resource.ID, EdgeContains, nil)
+	return resource
+}
+
+// addIdentities links compute resources to the managed identities attached to them, so
+// control of a resource leads on to the roles its identities hold
+func addIdentities(g *Graph, attachments []models.IdentityAttachment) {
+	for _, attachment := range attachments {
+		resource := addScope(g, attachment.ResourceID, attachment.ResourceName, map[string]string{"type": attachment.ResourceType})
+
+		properties := map[string]string{"identityType": attachment.IdentityType}
+		if attachment.ClientID != "" {
+			properties["identifier"] = attachment.ClientID
+		}
+		identity := g.AddNode(attachment.PrincipalID, KindManagedIdentity, attachment.Name(), properties)
+
+		g.AddEdge(resource.ID, identity.ID, EdgeHasIdentity, nil)
+	}
+}
+
+func addRoleAssignments(g *Graph, assignments []models.ResolvedRoleAssignment, definitions []models.RoleDefinition, engine *rbac.Engine) {
+	access := make(map[string]string, len(definitions))
+	for _, definition := range definitions {
+		access[models.RoleDefinitionKey(definition.ID)] = roleAccess(definition, engine)
+	}
+
+	for _, assignment := range assignments {
+		level, ok := access[models.RoleDefinitionKey(assignment.RoleDefinitionID)]
+		if !ok {
+			level = AccessRead
+		}
+
+		// A condition can restrict what the role grants, so do not count on it for escalation
+		if assignment.Condition != "" && level == AccessOwner {
+			level = AccessPrivileged
+		}
+
+		addPrincipal(g, assignment.Principal)
+		scope := addScope(g, assignment.Scope, "", nil)
+
+		g.AddEdge(assignment.Principal.ID, scope.ID, EdgeHasRole, map[string]string{
+			"role":   assignment.RoleName,
+			"access": level,
+		})
+	}
+}
+
+// roleAccess classifies a role definition by what it allows for privilege escalation
+func roleAccess(role models.RoleDefinition, engine *rbac.Engine) string {
+	if _, ok := rbac.Allows(role.Properties.Permissions, roleAssignmentWrite, false); ok {
+		return AccessOwner
+	}
+	if len(engine.EvaluateRole(role)) > 0 {
+		return AccessPrivileged
+	}
+	return AccessRead
+}
@@ -0,0 +1,62 @@
+package attackpath
+
+import (
+	"testing"
+
+	"github.com/f0rk3b0mb/GoCloudGhost/azure/graph"
+	"github.com/f0rk3b0mb/GoCloudGhost/azure/models"
+)
+
+func TestAddApplications(t *testing.T) {
+	g := New()
+	addApplications(g, []models.AppAudit{
+		{
+			ObjectID:    "app-object",
+			AppID:       "app-id",
+			DisplayName: "Deployer",
+			Kind:        models.AppAuditKindApplication,
+			Owners:      []models.Principal{{ID: "alice", Type: models.PrincipalTypeUser}},
+		},
+		{
+			ObjectID:             "sp-object",
+			AppID:                "app-id",
+			DisplayName:          "Deployer",
+			Kind:                 models.AppAuditKindServicePrincipal,
+			ServicePrincipalType: "Application",
+			AppPermissions:       []string{"Microsoft Graph/RoleManagement.ReadWrite.Directory"},
+		},
+		{
+			ObjectID:             "mi-object",
+			AppID:                "mi-app-id",
+			DisplayName:          "vm-identity",
+			Kind:                 models.AppAuditKindServicePrincipal,
+			ServicePrincipalType: "ManagedIdentity",
+		},
+	})
+
+	kinds := map[string]string{
+		"app-object": KindApplication,
+		"sp-object":  KindServicePrincipal,
+		"mi-object":  KindManagedIdentity,
+	}
+	for id, kind := range kinds {
+		if node := g.Node(id); node == nil || node.Kind != kind {
+			t.Errorf("node %s = %+v, want kind %s", id, node, kind)
+		}
+	}
+
+	paths := g.ShortestPaths("alice")
+	if len(paths) != 1 || paths[0].Target.ID != normalizeID("directoryrole:"+graph.GlobalAdministratorTemplateID) {
+		t.Fatalf("ShortestPaths(alice) = %+v, want one path to Global Administrator", paths)
+	}
+
+	want := []string{EdgeOwns, EdgeAuthenticatesAs, EdgeCanGrant}
+	if len(paths[0].Edges) != len(want) {
+		t.Fatalf("path has %d edges, want %v", len(paths[0].Edges), want)
+	}
+	for i, edge := range paths[0].Edges {
+		if edge.Kind != want[i] {
+			t.Errorf("edge %d = %s, want %s", i, edge.Kind, want[i])
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package attackpath
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/f0rk3b0mb/GoCloudGhost/azure/auth"
+	"github.com/f0rk3b0mb/GoCloudGhost/azure/models"
+	"github.com/f0rk3b0mb/GoCloudGhost/azure/rbac"
+	"github.com/joho/godotenv"
+	"github.com/spf13/cobra"
+)
+
+var PathsCmd = &cobra.Command{
+	Use:   "attack-paths",
+	Short: "Find privilege escalation paths from the current identity using collected session data",
+	Long: `Links the principals, group memberships, app ownership, directory roles, role
+assignments and resources saved by earlier azure commands into a graph, and prints the
+shortest path from the current identity to Global Administrator and to owner-level
+access on subscriptions and management groups.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		from, _ := cmd.Flags().GetString("from")
+		token, _ := cmd.Flags().GetString("token")
+		rulesFile, _ := cmd.Flags().GetString("rules")
+
+		engine, err := rbac.NewEngine(rulesFile)
+		if err != nil {
+			return err
+		}
+
+		if from == "" {
+			from, err = currentIdentity(token)
+			if err != nil {
+				return err
+			}
+		}
+
+		return findAttackPaths(from, engine)
+	},
+}
+
+func init() {
+	PathsCmd.Flags().String("token", "", "Azure access token identifying the start principal (defaults to ACCESS_TOKEN)")
+	PathsCmd.Flags().String("from", "", "Object ID of the principal to start from instead of the token's")
+	PathsCmd.Flags().String("rules", "", "YAML file with additional RBAC risk rules")
+}
+
+// currentIdentity returns the object ID of the principal the ARM token was issued to
+func currentIdentity(token string) (string, error) {
+	if token == "" {
+		_ = godotenv.Load()
+		token = os.Getenv("ACCESS_TOKEN")
+	}
+	if token == "" {
+		return "", fmt.Errorf("an access token or --from is required to identify the start principal")
+	}
+
+	claims, err := auth.ParseTokenClaims(token)
+	if err != nil {
+		return "", err
+	}
+	if claims.ObjectID == "" {
+		return "", fmt.Errorf("token has no oid claim, use --from")
+	}
+
+	return claims.ObjectID, nil
+}
+
+func findAttackPaths(from string, engine *rbac.Engine) error {
+	g := New()
+
+	loaded, err := LoadAzure(g, engine)
+	if err != nil {
+		return err
+	}
+	if len(loaded) == 0 {
+		return fmt.Errorf("no session data found, run the azure management and graph commands first")
+	}
+
+	fmt.Println("\n=== ATTACK GRAPH ===")
+	fmt.Printf("[INFO] Sources: %s\n", strings.Join(loaded, ", "))
+	fmt.Printf("[INFO] Nodes: %d  Edges: %d\n", len(g.Nodes), len(g.Edges))
+
+	start := g.Node(from)
+	if start == nil {
+		fmt.Printf("[WARN] Principal %s does not appear in the collected data, it holds no known roles or memberships\n", from)
+		return nil
+	}
+
+	fmt.Println("\n=== ATTACK PATHS ===")
+
+	paths := g.ShortestPaths(start.ID)
+	if len(paths) == 0 {
+		fmt.Printf("[INFO] No path to a high-value target from %s\n", start.Label())
+		return nil
+	}
+
+	for _, path := range paths {
+		severity := models.SeverityHigh
+		if len(path.Edges) <= 2 {
+			severity = models.SeverityCritical
+		}
+
+		fmt.Println(models.Finding{
+			Severity: severity,
+			Resource: start.Label(),
+			Title:    "Path to " + path.Description,
+			Evidence: fmt.Sprintf("%d hops", len(path.Edges)),
+		})
+
+		fmt.Printf("    %s (%s)\n", start.Label(), start.Kind)
+		for _, edge := range path.Edges {
+			fmt.Printf("      %s\n", g.Describe(edge))
+		}
+	}
+
+	return nil
+}
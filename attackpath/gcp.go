@@ -0,0 +1,44 @@
+package attackpath
+
+import (
+	"strings"
+
+	gcplist "github.com/f0rk3b0mb/GoCloudGhost/gcp/list"
+)
+
+// LoadGCPImpersonation adds the service accounts found by `gcp list impersonate` and the
+// callers that could impersonate them. Node IDs are lower-cased emails.
+func LoadGCPImpersonation(g *Graph) ([]string, error) {
+	var loader sessionLoader
+
+	var results []gcplist.ImpersonationResult
+	if err := loader.load(gcplist.ImpersonationSession, &results); err != nil {
+		return nil, err
+	}
+
+	for _, result := range results {
+		project := g.AddNode("projects/"+result.ProjectID, KindGCPProject, result.ProjectID, nil)
+
+		if result.Caller != "" {
+			kind := KindGCPUser
+			if strings.HasSuffix(strings.ToLower(result.Caller), ".gserviceaccount.com") {
+				kind = KindGCPServiceAccount
+			}
+			g.AddNode(result.Caller, kind, result.Caller, nil)
+		}
+
+		for _, account := range result.ServiceAccounts {
+			g.AddNode(account.Email, KindGCPServiceAccount, account.Email, map[string]string{
+				"uniqueId":    account.UniqueID,
+				"displayName": account.DisplayName,
+			})
+			g.AddEdge(project.ID, account.Email, EdgeContains, nil)
+
+			if account.Impersonable {
+				g.AddEdge(result.Caller, account.Email, EdgeCanImpersonate, nil)
+			}
+		}
+	}
+
+	return loader.loaded, nil
+}
@@ -0,0 +1,174 @@
+// Package attackpath links the principals, roles and resources collected by the
+// enumeration commands into a graph and searches it for privilege escalation paths.
+package attackpath
+
+import (
+	"sort"
+	"strings"
+)
+
+// Node kinds. Principal kinds reuse the names of models.PrincipalType*.
+const (
+	KindUser              = "User"
+	KindGroup             = "Group"
+	KindServicePrincipal  = "ServicePrincipal"
+	KindManagedIdentity   = "ManagedIdentity"
+	KindApplication       = "Application"
+	KindDirectoryRole     = "DirectoryRole"
+	KindManagementGroup   = "ManagementGroup"
+	KindSubscription      = "Subscription"
+	KindResourceGroup     = "ResourceGroup"
+	KindResource          = "Resource"
+	KindGCPProject        = "GCPProject"
+	KindGCPUser           = "GCPUser"
+	KindGCPServiceAccount = "GCPServiceAccount"
+)
+
+// Edge kinds
+const (
+	// EdgeMemberOf links a principal to a group it is a member of
+	EdgeMemberOf = "MemberOf"
+	// EdgeOwns links an owner to the application or service principal it can add credentials to
+	EdgeOwns = "Owns"
+	// EdgeAuthenticatesAs links an app registration to its service principal, credentials
+	// added to the app sign in as the service principal
+	EdgeAuthenticatesAs = "AuthenticatesAs"
+	// EdgeAddSecret links a directory role to the applications its holders can add credentials to
+	EdgeAddSecret = "AddSecret"
+	// EdgeHasDirectoryRole links a principal to a tenant-wide directory role it holds
+	EdgeHasDirectoryRole = "HasDirectoryRole"
+	// EdgeCanGrant links a directory role or application permission holder to the
+	// directory role it can grant itself
+	EdgeCanGrant = "CanGrant"
+	// EdgeResetPassword links a directory role to a role whose holders' passwords it can reset
+	EdgeResetPassword = "ResetPassword"
+	// EdgeHasRole links a principal to an Azure RBAC scope it holds a role on
+	EdgeHasRole = "HasRole"
+	// EdgeContains links an Azure scope to the scopes below it, which inherit its assignments
+	EdgeContains = "Contains"
+	// EdgeHasIdentity links a resource to a managed identity attached to it
+	EdgeHasIdentity = "HasIdentity"
+	// EdgeCanImpersonate links a GCP principal to a service account it can mint tokens for
+	EdgeCanImpersonate = "CanImpersonate"
+)
+
+// Access levels of an EdgeHasRole edge, stored in its "access" property
+const (
+	// AccessOwner roles can create role assignments and so take over the scope
+	AccessOwner = "owner"
+	// AccessPrivileged roles fire a risk rule, such as code execution or secret reads
+	AccessPrivileged = "privileged"
+	// AccessRead roles grant nothing useful for escalation
+	AccessRead = "read"
+)
+
+type Node struct {
+	ID         string
+	Kind       string
+	Name       string
+	Properties map[string]string
+}
+
+// Label renders a node for output
+func (n Node) Label() string {
+	if n.Name == "" || n.Name == n.ID {
+		return n.ID
+	}
+	return n.Name
+}
+
+type Edge struct {
+	From       string
+	To         string
+	Kind       string
+	Properties map[string]string
+}
+
+// Graph is a directed graph of principals, roles and scopes. Node IDs are object IDs for
+// directory objects and lower-cased resource IDs for Azure scopes.
+type Graph struct {
+	Nodes map[string]*Node
+	Edges []Edge
+
+	outgoing map[string][]int
+	edgeKeys map[string]bool
+}
+
+func New() *Graph {
+	return &Graph{
+		Nodes:    make(map[string]*Node),
+		outgoing: make(map[string][]int),
+		edgeKeys: make(map[string]bool),
+	}
+}
+
+// AddNode adds a node or fills in the name, kind and properties of an existing one.
+// Later sources are often better resolved than earlier ones, so non-empty values win.
+func (g *Graph) AddNode(id, kind, name string, properties map[string]string) *Node {
+	id = normalizeID(id)
+
+	node, ok := g.Nodes[id]
+	if !ok {
+		node = &Node{ID: id, Kind: kind, Name: name, Properties: make(map[string]string)}
+		g.Nodes[id] = node
+	}
+
+	if name != "" && (node.Name == "" || node.Name == id) {
+		node.Name = name
+	}
+	if kind != "" && (node.Kind == "" || node.Kind == "Unknown") {
+		node.Kind = kind
+	}
+	for key, value := range properties {
+		node.Properties[key] = value
+	}
+
+	return node
+}
+
+// AddEdge adds an edge between two existing nodes, ignoring duplicates
+func (g *Graph) AddEdge(from, to, kind string, properties map[string]string) {
+	from, to = normalizeID(from), normalizeID(to)
+	if from == to || g.Nodes[from] == nil || g.Nodes[to] == nil {
+		return
+	}
+
+	key := from + "|" + to + "|" + kind + "|" + properties["role"]
+	if g.edgeKeys[key] {
+		return
+	}
+	g.edgeKeys[key] = true
+
+	g.Edges = append(g.Edges, Edge{From: from, To: to, Kind: kind, Properties: properties})
+	g.outgoing[from] = append(g.outgoing[from], len(g.Edges)-1)
+}
+
+// Node returns the node with the given ID, or nil
+func (g *Graph) Node(id string) *Node {
+	return g.Nodes[normalizeID(id)]
+}
+
+// SortedNodes returns the nodes ordered by kind and label, for stable exports
+func (g *Graph) SortedNodes() []*Node {
+	nodes := make([]*Node, 0, len(g.Nodes))
+	for _, node := range g.Nodes {
+		nodes = append(nodes, node)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].Kind != nodes[j].Kind {
+			return nodes[i].Kind < nodes[j].Kind
+		}
+		if nodes[i].Label() != nodes[j].Label() {
+			return nodes[i].Label() < nodes[j].Label()
+		}
+		return nodes[i].ID < nodes[j].ID
+	})
+	return nodes
+}
+
+func normalizeID(id string) string {
+	if len(id) > 1 {
+		id = strings.TrimSuffix(id, "/")
+	}
+	return strings.ToLower(id)
+}
@@ -0,0 +1,142 @@
+package attackpath
+
+import (
+	"sort"
+
+	"github.com/f0rk3b0mb/GoCloudGhost/azure/graph"
+)
+
+// Path is a shortest sequence of edges from the start node to a high-value target
+type Path struct {
+	Target      *Node
+	Description string
+	Edges       []Edge
+}
+
+// step is a search state. Scope nodes are reached with the access level of the role
+// assignment that led to them, which decides where the search may continue.
+type step struct {
+	node   string
+	access string
+}
+
+// ShortestPaths searches breadth first from start and returns the shortest path to every
+// reachable high-value target: Global Administrator, and owner-level access to a
+// subscription or management group.
+func (g *Graph) ShortestPaths(start string) []Path {
+	start = normalizeID(start)
+	if g.Nodes[start] == nil {
+		return nil
+	}
+
+	type visit struct {
+		previous *step
+		edge     Edge
+	}
+
+	origin := step{node: start}
+	visited := map[step]visit{origin: {}}
+	queue := []step{origin}
+
+	var paths []Path
+	reported := make(map[string]bool)
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		if description, ok := g.targetDescription(current); ok && !reported[current.node] && current.node != start {
+			reported[current.node] = true
+
+			var edges []Edge
+			for at := current; at != origin; {
+				v := visited[at]
+				edges = append([]Edge{v.edge}, edges...)
+				at = *v.previous
+			}
+
+			paths = append(paths, Path{Target: g.Nodes[current.node], Description: description, Edges: edges})
+		}
+
+		for _, index := range g.outgoing[current.node] {
+			edge := g.Edges[index]
+
+			next, ok := traverse(current, edge)
+			if !ok {
+				continue
+			}
+			if _, seen := visited[next]; seen {
+				continue
+			}
+
+			previous := current
+			visited[next] = visit{previous: &previous, edge: edge}
+			queue = append(queue, next)
+		}
+	}
+
+	sort.SliceStable(paths, func(i, j int) bool {
+		return len(paths[i].Edges) < len(paths[j].Edges)
+	})
+
+	return paths
+}
+
+// traverse decides whether the search may follow edge from the current state
+func traverse(current step, edge Edge) (step, bool) {
+	switch edge.Kind {
+	case EdgeHasRole:
+		access := edge.Properties["access"]
+		if access == AccessRead {
+			return step{}, false
+		}
+		return step{node: edge.To, access: access}, true
+	case EdgeContains:
+		// Inherited assignments apply below the scope at the same level
+		return step{node: edge.To, access: current.access}, true
+	case EdgeHasIdentity:
+		// Code execution on the resource is needed to obtain a token for its identity
+		if current.access == "" {
+			return step{}, false
+		}
+		return step{node: edge.To}, true
+	default:
+		// Directory relationships only continue from principals, not from Azure scopes
+		if current.access != "" {
+			return step{}, false
+		}
+		return step{node: edge.To}, true
+	}
+}
+
+func (g *Graph) targetDescription(current step) (string, bool) {
+	node := g.Nodes[current.node]
+
+	switch node.Kind {
+	case KindDirectoryRole:
+		if node.Properties["templateId"] == graph.GlobalAdministratorTemplateID {
+			return "Global Administrator", true
+		}
+	case KindSubscription:
+		if current.access == AccessOwner {
+			return "Owner of subscription " + node.Label(), true
+		}
+	case KindManagementGroup:
+		if current.access == AccessOwner {
+			return "Owner of management group " + node.Label(), true
+		}
+	}
+
+	return "", false
+}
+
+// Describe renders an edge for output
+func (g *Graph) Describe(edge Edge) string {
+	kind := edge.Kind
+	if role := edge.Properties["role"]; role != "" {
+		kind += " " + role
+	}
+
+	to := g.Nodes[edge.To]
+	return "-[" + kind + "]-> " + to.Label() + " (" + to.Kind + ")"
+}
@@ -0,0 +1,171 @@
+package attackpath
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/f0rk3b0mb/GoCloudGhost/azure/graph"
+)
+
+const (
+	testSubscription    = "/subscriptions/00000000-0000-0000-0000-000000000001"
+	testResourceGroup   = testSubscription + "/resourceGroups/rg"
+	testVM              = testResourceGroup + "/providers/Microsoft.Compute/virtualMachines/vm"
+	testManagementGroup = "/providers/Microsoft.Management/managementGroups/mg"
+)
+
+// testGraph builds a small tenant:
+//
+//	alice -MemberOf-> admins -HasRole(owner)-> mg -Contains-> subscription
+//	bob -HasRole(privileged)-> rg -Contains-> vm -HasIdentity-> identity -HasRole(owner)-> subscription
+//	carol -HasRole(read)-> subscription
+//	dave -Owns-> app -AuthenticatesAs-> sp -CanGrant-> Global Administrator
+//	dave -HasDirectoryRole-> Privileged Role Administrator -CanGrant-> Global Administrator
+//	erin -HasRole(owner)-> rg -Contains-> vm -Owns-> app
+func testGraph() *Graph {
+	g := New()
+
+	for id, kind := range map[string]string{
+		"alice":    KindUser,
+		"bob":      KindUser,
+		"carol":    KindUser,
+		"dave":     KindUser,
+		"erin":     KindUser,
+		"admins":   KindGroup,
+		"identity": KindManagedIdentity,
+		"app":      KindApplication,
+		"sp":       KindServicePrincipal,
+	} {
+		g.AddNode(id, kind, id, nil)
+	}
+
+	g.AddNode(testManagementGroup, KindManagementGroup, "mg", nil)
+	g.AddNode(testSubscription, KindSubscription, "subscription", nil)
+	g.AddNode(testResourceGroup, KindResourceGroup, "rg", nil)
+	g.AddNode(testVM, KindResource, "vm", nil)
+	globalAdmin := addDirectoryRole(g, graph.GlobalAdministratorTemplateID, "Global Administrator")
+	privilegedRoleAdmin := addDirectoryRole(g, privilegedRoleAdministratorTemplateID, "Privileged Role Administrator")
+
+	role := func(name, access string) map[string]string {
+		return map[string]string{"role": name, "access": access}
+	}
+
+	g.AddEdge("alice", "admins", EdgeMemberOf, nil)
+	g.AddEdge("admins", testManagementGroup, EdgeHasRole, role("Owner", AccessOwner))
+	g.AddEdge(testManagementGroup, testSubscription, EdgeContains, nil)
+	g.AddEdge(testSubscription, testResourceGroup, EdgeContains, nil)
+	g.AddEdge(testResourceGroup, testVM, EdgeContains, nil)
+
+	g.AddEdge("bob", testResourceGroup, EdgeHasRole, role("Virtual Machine Contributor", AccessPrivileged))
+	g.AddEdge(testVM, "identity", EdgeHasIdentity, nil)
+	g.AddEdge("identity", testSubscription, EdgeHasRole, role("Owner", AccessOwner))
+
+	g.AddEdge("carol", testSubscription, EdgeHasRole, role("Reader", AccessRead))
+
+	g.AddEdge("dave", "app", EdgeOwns, nil)
+	g.AddEdge("app", "sp", EdgeAuthenticatesAs, nil)
+	g.AddEdge("sp", globalAdmin.ID, EdgeCanGrant, nil)
+	g.AddEdge("dave", privilegedRoleAdmin.ID, EdgeHasDirectoryRole, nil)
+	g.AddEdge(privilegedRoleAdmin.ID, globalAdmin.ID, EdgeCanGrant, nil)
+
+	g.AddEdge("erin", testResourceGroup, EdgeHasRole, role("Owner", AccessOwner))
+	g.AddEdge(testVM, "app", EdgeOwns, nil)
+
+	return g
+}
+
+func TestShortestPaths(t *testing.T) {
+	tests := []struct {
+		start string
+		// want maps each expected target description to the edge kinds of its path
+		want map[string]string
+	}{
+		{
+			start: "alice",
+			want: map[string]string{
+				"Owner of management group mg":       "MemberOf,HasRole",
+				"Owner of subscription subscription": "MemberOf,HasRole,Contains",
+			},
+		},
+		{
+			// Code execution on the VM yields its identity, which owns the subscription
+			start: "bob",
+			want: map[string]string{
+				"Owner of subscription subscription": "HasRole,Contains,HasIdentity,HasRole",
+			},
+		},
+		{
+			// Read access is not followed
+			start: "carol",
+			want:  map[string]string{},
+		},
+		{
+			// The directory role is one hop shorter than the app credential route
+			start: "dave",
+			want: map[string]string{
+				"Global Administrator": "HasDirectoryRole,CanGrant",
+			},
+		},
+		{
+			// Owner of a resource group is not owner of the subscription, and directory
+			// relationships of a resource are not followed from an Azure scope
+			start: "erin",
+			want: map[string]string{
+				"Owner of subscription subscription": "HasRole,Contains,HasIdentity,HasRole",
+			},
+		},
+		{
+			start: "unknown",
+			want:  map[string]string{},
+		},
+	}
+
+	g := testGraph()
+
+	for _, tt := range tests {
+		t.Run(tt.start, func(t *testing.T) {
+			paths := g.ShortestPaths(tt.start)
+
+			got := make(map[string]string, len(paths))
+			for _, path := range paths {
+				kinds := make([]string, 0, len(path.Edges))
+				for _, edge := range path.Edges {
+					kinds = append(kinds, edge.Kind)
+				}
+				got[path.Description] = strings.Join(kinds, ",")
+			}
+
+			if len(got) != len(tt.want) {
+				t.Errorf("ShortestPaths(%q) found %v, want %v", tt.start, got, tt.want)
+			}
+			for description, kinds := range tt.want {
+				if got[description] != kinds {
+					t.Errorf("ShortestPaths(%q) path to %q = %q, want %q", tt.start, description, got[description], kinds)
+				}
+			}
+
+			for i := 1; i < len(paths); i++ {
+				if len(paths[i].Edges) < len(paths[i-1].Edges) {
+					t.Errorf("ShortestPaths(%q) not sorted by length", tt.start)
+				}
+			}
+		})
+	}
+}
+
+func TestShortestPathsEdgesConnect(t *testing.T) {
+	g := testGraph()
+
+	for _, path := range g.ShortestPaths("bob") {
+		at := "bob"
+		for _, edge := range path.Edges {
+			if edge.From != at {
+				t.Fatalf("path to %q breaks at %s: edge starts at %s", path.Description, at, edge.From)
+			}
+			at = edge.To
+		}
+		if at != path.Target.ID {
+			t.Errorf("path to %q ends at %s, want %s", path.Description, at, path.Target.ID)
+		}
+	}
+}
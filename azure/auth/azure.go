@@ -61,7 +61,37 @@ func saveEnv(path string, env map[string]string) error {
 ======================= */
 
 func Authenticate(clientID, clientSecret, tenantID string) error {
-	scope := "https://management.azure.com/.default"
+	accessToken, err := requestToken(clientID, clientSecret, tenantID, "https://management.azure.com/.default")
+	if err != nil {
+		return err
+	}
+
+	env, err := loadEnv("./.env")
+	if err != nil {
+		return err
+	}
+
+	env["AZURE_TENANT_ID"] = tenantID
+
+	// A Graph token is optional, the service principal may not have any Graph permissions
+	graphToken, err := requestToken(clientID, clientSecret, tenantID, "https://graph.microsoft.com/.default")
+	if err != nil {
+		fmt.Printf("[WARN] Could not acquire Microsoft Graph token: %v\n", err)
+	} else {
+		env["GRAPH_ACCESS_TOKEN"] = graphToken
+	}
+
+	if err := saveEnv("./.env", env); err != nil {
+		return err
+	}
+
+	fmt.Println("Access token acquired and stored")
+
+	return EnumerateSubscriptions(accessToken)
+}
+
+// requestToken performs a client credentials grant for the given scope
+func requestToken(clientID, clientSecret, tenantID, scope string) (string, error) {
 	tokenURL := fmt.Sprintf(
 		"https://login.microsoftonline.com/%s/oauth2/v2.0/token",
 		tenantID,
@@ -80,20 +110,20 @@ func Authenticate(clientID, clientSecret, tenantID string) error {
 		strings.NewReader(form.Encode()),
 	)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return err
+		return "", err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("token request failed: %s\n%s", resp.Status, body)
+		return "", fmt.Errorf("token request failed: %s\n%s", resp.Status, body)
 	}
 
 	var tokenResp struct {
@@ -102,27 +132,14 @@ func Authenticate(clientID, clientSecret, tenantID string) error {
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
-		return err
+		return "", err
 	}
 
 	if tokenResp.AccessToken == "" {
-		return fmt.Errorf("no access_token in response")
+		return "", fmt.Errorf("no access_token in response")
 	}
 
-	env, err := loadEnv("./.env")
-	if err != nil {
-		return err
-	}
-
-	env["AZURE_TENANT_ID"] = tenantID
-
-	if err := saveEnv("./.env", env); err != nil {
-		return err
-	}
-
-	fmt.Println("Access token acquired and stored")
-
-	return EnumerateSubscriptions(tokenResp.AccessToken)
+	return tokenResp.AccessToken, nil
 }
 
 /* =======================
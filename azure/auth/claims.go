@@ -0,0 +1,56 @@
+package auth
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// TokenClaims holds the identity related claims of an Entra ID access token
+type TokenClaims struct {
+	ObjectID   string   `json:"oid"`
+	TenantID   string   `json:"tid"`
+	AppID      string   `json:"appid"`
+	Audience   string   `json:"aud"`
+	UPN        string   `json:"upn"`
+	UniqueName string   `json:"unique_name"`
+	IDType     string   `json:"idtyp"`
+	Roles      []string `json:"roles"`
+	WIDs       []string `json:"wids"`
+	Expires    int64    `json:"exp"`
+}
+
+// ParseTokenClaims decodes the payload of a JWT access token without validating its signature
+func ParseTokenClaims(token string) (*TokenClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("token is not a JWT")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode token payload: %w", err)
+	}
+
+	var claims TokenClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("failed to parse token claims: %w", err)
+	}
+
+	return &claims, nil
+}
+
+// Identity returns the most readable name for the token's principal
+func (c *TokenClaims) Identity() string {
+	switch {
+	case c.UPN != "":
+		return c.UPN
+	case c.UniqueName != "":
+		return c.UniqueName
+	case c.AppID != "":
+		return "app " + c.AppID
+	default:
+		return c.ObjectID
+	}
+}
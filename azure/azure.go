@@ -1,9 +1,11 @@
 package azure
 
 import (
+	"github.com/f0rk3b0mb/GoCloudGhost/attackpath"
 	"github.com/f0rk3b0mb/GoCloudGhost/azure/auth"
 	blob "github.com/f0rk3b0mb/GoCloudGhost/azure/blob"
 	management "github.com/f0rk3b0mb/GoCloudGhost/azure/enum"
+	"github.com/f0rk3b0mb/GoCloudGhost/azure/graph"
 	"github.com/spf13/cobra"
 )
 
@@ -15,5 +17,8 @@ var AzureCmd = &cobra.Command{
 func init() {
 	AzureCmd.AddCommand(blob.BlobCmd)
 	AzureCmd.AddCommand(management.MgmtCmd)
+	AzureCmd.AddCommand(management.ResourceGraphCmd)
 	AzureCmd.AddCommand(auth.AuthCmd)
+	AzureCmd.AddCommand(graph.GraphCmd)
+	AzureCmd.AddCommand(attackpath.PathsCmd)
 }
@@ -0,0 +1,101 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+)
+
+// ErrKeyRejected is returned by EnumerateAccount when the shared key is refused before
+// anything has been listed, so the caller can safely retry with another key
+var ErrKeyRejected = errors.New("shared key rejected")
+
+// NewServiceClient creates a blob service client authenticated with a storage account shared key
+func NewServiceClient(account, key string) (*azblob.Client, error) {
+	url := fmt.Sprintf("https://%s.blob.core.windows.net/", account)
+
+	cred, err := azblob.NewSharedKeyCredential(account, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create credential: %w", err)
+	}
+
+	serviceClient, err := azblob.NewClientWithSharedKeyCredential(url, cred, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create service client: %w", err)
+	}
+
+	return serviceClient, nil
+}
+
+// EnumerateAccount lists every container in a storage account together with the blobs it holds
+func EnumerateAccount(account, key string) error {
+	ctx := context.Background()
+
+	serviceClient, err := NewServiceClient(account, key)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrKeyRejected, err)
+	}
+
+	first := true
+	pager := serviceClient.NewListContainersPager(nil)
+	for pager.More() {
+		resp, err := pager.NextPage(ctx)
+		if err != nil {
+			if first && bloberror.HasCode(err, bloberror.AuthenticationFailed, bloberror.AuthorizationFailure, bloberror.AuthorizationPermissionMismatch) {
+				return fmt.Errorf("%w: %v", ErrKeyRejected, err)
+			}
+			return fmt.Errorf("failed to list containers: %w", err)
+		}
+		first = false
+
+		for _, item := range resp.ContainerItems {
+			if item.Name == nil {
+				continue
+			}
+
+			access := "private"
+			if item.Properties != nil && item.Properties.PublicAccess != nil {
+				access = string(*item.Properties.PublicAccess)
+			}
+
+			fmt.Printf("[INFO] Container: %-40s Public Access: %s\n", *item.Name, access)
+
+			if err := listContainerBlobs(ctx, serviceClient, *item.Name); err != nil {
+				fmt.Printf("[WARN] Blob listing failed for %s/%s: %v\n", account, *item.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func listContainerBlobs(ctx context.Context, serviceClient *azblob.Client, container string) error {
+	containerClient := serviceClient.ServiceClient().NewContainerClient(container)
+
+	count := 0
+	pager := containerClient.NewListBlobsFlatPager(nil)
+	for pager.More() {
+		resp, err := pager.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, blob := range resp.Segment.BlobItems {
+			var size int64
+			if blob.Properties != nil && blob.Properties.ContentLength != nil {
+				size = *blob.Properties.ContentLength
+			}
+			fmt.Printf("    Blob: %-60s Size: %d\n", *blob.Name, size)
+			count++
+		}
+	}
+
+	if count == 0 {
+		fmt.Println("    (empty)")
+	}
+
+	return nil
+}
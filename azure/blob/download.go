@@ -2,12 +2,10 @@ package blob
 
 import (
 	"context"
-	"fmt"
 	"io"
 	"log"
 	"os"
 
-	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
 	"github.com/spf13/cobra"
 )
 
@@ -50,14 +48,8 @@ func init() {
 
 func downloadBlob(account, key, container, blobName, output string) error {
 	ctx := context.Background()
-	url := fmt.Sprintf("https://%s.blob.core.windows.net/", account)
 
-	cred, err := azblob.NewSharedKeyCredential(account, key)
-	if err != nil {
-		return err
-	}
-
-	serviceClient, err := azblob.NewClientWithSharedKeyCredential(url, cred, nil)
+	serviceClient, err := NewServiceClient(account, key)
 	if err != nil {
 		return err
 	}
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"log"
 
-	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
 	"github.com/spf13/cobra"
 )
 
@@ -42,16 +41,10 @@ func init() {
 
 func listBlobs(account, key, container string) error {
 	ctx := context.Background()
-	url := fmt.Sprintf("https://%s.blob.core.windows.net/", account)
 
-	cred, err := azblob.NewSharedKeyCredential(account, key)
+	serviceClient, err := NewServiceClient(account, key)
 	if err != nil {
-		return fmt.Errorf("failed to create credential: %w", err)
-	}
-
-	serviceClient, err := azblob.NewClientWithSharedKeyCredential(url, cred, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create service client: %w", err)
+		return err
 	}
 
 	containerClient := serviceClient.ServiceClient().NewContainerClient(container)
@@ -0,0 +1,219 @@
+package management
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/f0rk3b0mb/GoCloudGhost/azure/models"
+)
+
+const automationAPIVersion = "2023-11-01"
+
+func enumerateAutomationAccounts(token, subscriptionID string) error {
+	ctx := context.Background()
+
+	url := fmt.Sprintf(
+		"https://management.azure.com/subscriptions/%s/providers/Microsoft.Automation/automationAccounts?api-version=%s",
+		subscriptionID,
+		automationAPIVersion,
+	)
+
+	accounts, err := listAllPages[models.AutomationAccount](ctx, token, url)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("\n=== AUTOMATION ACCOUNTS ===")
+
+	if len(accounts) == 0 {
+		fmt.Println("[INFO] No automation accounts found.")
+		return nil
+	}
+
+	for _, account := range accounts {
+		fmt.Printf("\n[INFO] Automation Account: %-25s Resource Group: %s\n",
+			account.Name,
+			extractResourceGroupFromID(account.ID),
+		)
+
+		base := "https://management.azure.com" + account.ID
+
+		enumerateRunbooks(ctx, token, base, account.Name)
+		enumerateAutomationVariables(ctx, token, base, account.Name)
+		enumerateAutomationCredentials(ctx, token, base, account.Name)
+		enumerateAutomationConnections(ctx, token, base, account.Name)
+		enumerateAutomationSchedules(ctx, token, base, account.Name)
+		enumerateHybridWorkerGroups(ctx, token, base, account.Name)
+	}
+
+	return nil
+}
+
+func enumerateRunbooks(ctx context.Context, token, base, accountName string) {
+	url := fmt.Sprintf("%s/runbooks?api-version=%s", base, automationAPIVersion)
+
+	runbooks, err := listAllPages[models.Runbook](ctx, token, url)
+	if err != nil {
+		fmt.Printf("[WARN] Runbook request failed for %s: %v\n", accountName, err)
+		return
+	}
+
+	for _, runbook := range runbooks {
+		fmt.Printf("[INFO] Runbook: %-35s Type: %-20s State: %s\n",
+			runbook.Name,
+			runbook.Properties.RunbookType,
+			runbook.Properties.State,
+		)
+
+		// "New" runbooks only have a draft, "Edit" runbooks have both a draft and a published version
+		if runbook.Properties.State != "New" {
+			downloadRunbookContent(ctx, token, base, accountName, runbook, "content", "")
+		}
+		if runbook.Properties.State != "Published" {
+			downloadRunbookContent(ctx, token, base, accountName, runbook, "draft/content", ".draft")
+		}
+	}
+}
+
+func downloadRunbookContent(ctx context.Context, token, base, accountName string, runbook models.Runbook, endpoint, suffix string) {
+	url := fmt.Sprintf("%s/runbooks/%s/%s?api-version=%s", base, runbook.Name, endpoint, automationAPIVersion)
+
+	content, err := makeAuthenticatedRawRequest(ctx, token, http.MethodGet, url)
+	if err != nil {
+		fmt.Printf("[WARN] Could not download %s for runbook %s: %v\n", endpoint, runbook.Name, err)
+		return
+	}
+
+	fileName := runbook.Name + suffix + runbookExtension(runbook.Properties.RunbookType)
+	path, err := saveLoot(content, "automation", accountName, fileName)
+	if err != nil {
+		fmt.Printf("[WARN] Could not save runbook %s: %v\n", runbook.Name, err)
+		return
+	}
+
+	fmt.Printf("[INFO] Runbook source saved to %s\n", path)
+}
+
+func runbookExtension(runbookType string) string {
+	switch runbookType {
+	case "Python", "Python2", "Python3":
+		return ".py"
+	case "GraphPowerShell", "GraphPowerShellWorkflow", "Script":
+		return ".graphrunbook"
+	default:
+		return ".ps1"
+	}
+}
+
+func enumerateAutomationVariables(ctx context.Context, token, base, accountName string) {
+	url := fmt.Sprintf("%s/variables?api-version=%s", base, automationAPIVersion)
+
+	variables, err := listAllPages[models.AutomationVariable](ctx, token, url)
+	if err != nil {
+		fmt.Printf("[WARN] Variable request failed for %s: %v\n", accountName, err)
+		return
+	}
+
+	for _, variable := range variables {
+		if variable.Properties.IsEncrypted {
+			fmt.Printf("[INFO] Variable: %-35s Encrypted: true\n", variable.Name)
+			continue
+		}
+
+		fmt.Println(models.Finding{
+			Severity: models.SeverityHigh,
+			Resource: accountName + "/" + variable.Name,
+			Title:    "Unencrypted automation variable",
+			Evidence: "value=" + variable.Properties.Value,
+		})
+	}
+}
+
+func enumerateAutomationCredentials(ctx context.Context, token, base, accountName string) {
+	url := fmt.Sprintf("%s/credentials?api-version=%s", base, automationAPIVersion)
+
+	credentials, err := listAllPages[models.AutomationCredential](ctx, token, url)
+	if err != nil {
+		fmt.Printf("[WARN] Credential request failed for %s: %v\n", accountName, err)
+		return
+	}
+
+	// The password is only exposed to running jobs, so a writable runbook is needed to recover it
+	for _, credential := range credentials {
+		fmt.Println(models.Finding{
+			Severity: models.SeverityMedium,
+			Resource: accountName + "/" + credential.Name,
+			Title:    "Credential asset stored in automation account",
+			Evidence: "userName=" + credential.Properties.UserName,
+		})
+	}
+}
+
+func enumerateAutomationConnections(ctx context.Context, token, base, accountName string) {
+	url := fmt.Sprintf("%s/connections?api-version=%s", base, automationAPIVersion)
+
+	connections, err := listAllPages[models.AutomationConnection](ctx, token, url)
+	if err != nil {
+		fmt.Printf("[WARN] Connection request failed for %s: %v\n", accountName, err)
+		return
+	}
+
+	for _, connection := range connections {
+		connectionType := connection.Properties.ConnectionType.Name
+
+		if connectionType == "AzureServicePrincipal" || connectionType == "AzureClassicCertificate" {
+			fmt.Println(models.Finding{
+				Severity: models.SeverityHigh,
+				Resource: accountName + "/" + connection.Name,
+				Title:    "Run As connection available to runbooks",
+				Evidence: "connectionType=" + connectionType,
+			})
+			continue
+		}
+
+		fmt.Printf("[INFO] Connection: %-35s Type: %s\n", connection.Name, connectionType)
+	}
+}
+
+func enumerateAutomationSchedules(ctx context.Context, token, base, accountName string) {
+	url := fmt.Sprintf("%s/schedules?api-version=%s", base, automationAPIVersion)
+
+	schedules, err := listAllPages[models.AutomationSchedule](ctx, token, url)
+	if err != nil {
+		fmt.Printf("[WARN] Schedule request failed for %s: %v\n", accountName, err)
+		return
+	}
+
+	for _, schedule := range schedules {
+		fmt.Printf("[INFO] Schedule: %-35s Frequency: %-10s Enabled: %-5t Next Run: %s\n",
+			schedule.Name,
+			schedule.Properties.Frequency,
+			schedule.Properties.IsEnabled,
+			schedule.Properties.NextRun,
+		)
+	}
+}
+
+func enumerateHybridWorkerGroups(ctx context.Context, token, base, accountName string) {
+	url := fmt.Sprintf("%s/hybridRunbookWorkerGroups?api-version=%s", base, automationAPIVersion)
+
+	groups, err := listAllPages[models.HybridWorkerGroup](ctx, token, url)
+	if err != nil {
+		fmt.Printf("[WARN] Hybrid worker group request failed for %s: %v\n", accountName, err)
+		return
+	}
+
+	for _, group := range groups {
+		credential := "none"
+		if group.Properties.Credential != nil {
+			credential = group.Properties.Credential.Name
+		}
+
+		fmt.Printf("[INFO] Hybrid Worker Group: %-25s Type: %-8s Run As Credential: %s\n",
+			group.Name,
+			group.Properties.GroupType,
+			credential,
+		)
+	}
+}
@@ -0,0 +1,203 @@
+package management
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/f0rk3b0mb/GoCloudGhost/azure/models"
+)
+
+const acrAPIVersion = "2023-07-01"
+
+// registryPageSize is the number of repositories or tags requested per registry API call
+const registryPageSize = 100
+
+// linkNextPattern extracts the next page from a Docker Registry v2 Link header
+var linkNextPattern = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// enumerateContainerRegistries lists registries, flags the admin user and anonymous pull,
+// and uses credentials from listCredentials to list repositories and tags
+func enumerateContainerRegistries(token, subscriptionID string) error {
+	ctx := context.Background()
+
+	url := fmt.Sprintf(
+		"https://management.azure.com/subscriptions/%s/providers/Microsoft.ContainerRegistry/registries?api-version=%s",
+		subscriptionID,
+		acrAPIVersion,
+	)
+
+	registries, err := listAllPages[models.ContainerRegistry](ctx, token, url)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("\n=== CONTAINER REGISTRIES ===")
+
+	if len(registries) == 0 {
+		fmt.Println("[INFO] No container registries found.")
+		return nil
+	}
+
+	var resources []models.Resource
+	for _, registry := range registries {
+		props := registry.Properties
+		sku := ""
+		if registry.SKU != nil {
+			sku = registry.SKU.Name
+		}
+
+		fmt.Printf("\n[INFO] Registry: %-30s Resource Group: %-25s SKU: %-9s Login Server: %s\n",
+			registry.Name,
+			extractResourceGroupFromID(registry.ID),
+			sku,
+			props.LoginServer,
+		)
+
+		resources = append(resources, models.Resource{
+			ID:       registry.ID,
+			Name:     registry.Name,
+			Type:     "Microsoft.ContainerRegistry/registries",
+			Location: registry.Location,
+		})
+
+		if props.AdminUserEnabled {
+			fmt.Println(models.Finding{Severity: models.SeverityHigh, Resource: registry.Name, Title: "Admin user enabled", Evidence: "adminUserEnabled=true"})
+		}
+		if props.AnonymousPullEnabled {
+			fmt.Println(models.Finding{Severity: models.SeverityHigh, Resource: registry.Name, Title: "Anonymous pull enabled", Evidence: "anonymousPullEnabled=true"})
+		}
+		if !strings.EqualFold(props.PublicNetworkAccess, "Disabled") {
+			fmt.Println(models.Finding{Severity: models.SeverityLow, Resource: registry.Name, Title: "Public network access enabled", Evidence: "publicNetworkAccess=" + valueOr(props.PublicNetworkAccess, "<unset>")})
+		}
+
+		// listCredentials only succeeds when the admin user is enabled
+		if !props.AdminUserEnabled {
+			continue
+		}
+
+		var credentials models.ContainerRegistryCredentials
+		credentialsURL := fmt.Sprintf("https://management.azure.com%s/listCredentials?api-version=%s", registry.ID, acrAPIVersion)
+		if err := makeAuthenticatedRequest(ctx, token, http.MethodPost, credentialsURL, &credentials); err != nil {
+			fmt.Printf("[WARN] Credential request failed for %s: %v\n", registry.Name, err)
+			continue
+		}
+
+		for _, password := range credentials.Passwords {
+			printKey(registry.Name, credentials.Username+" "+password.Name, password.Value)
+		}
+
+		if len(credentials.Passwords) > 0 {
+			lootRegistry(ctx, registry.Name, props.LoginServer, credentials.Username, credentials.Passwords[0].Value)
+		}
+	}
+
+	recordResources(resources...)
+
+	return nil
+}
+
+// lootRegistry lists the repositories and tags of a registry through the Docker Registry
+// v2 API and saves them so images can be pulled for review
+func lootRegistry(ctx context.Context, name, loginServer, username, password string) {
+	client := registryClient{server: loginServer, username: username, password: password}
+
+	var repositories []string
+	next := fmt.Sprintf("/v2/_catalog?n=%d", registryPageSize)
+	for next != "" {
+		var catalog models.RegistryCatalog
+		var err error
+		if next, err = client.get(ctx, next, &catalog); err != nil {
+			fmt.Printf("[WARN] Catalog request failed for %s: %v\n", name, err)
+			return
+		}
+		repositories = append(repositories, catalog.Repositories...)
+	}
+
+	fmt.Printf("\n--- Repositories in %s ---\n", name)
+
+	if len(repositories) == 0 {
+		fmt.Println("[INFO] No repositories found.")
+		return
+	}
+
+	catalog := make(map[string][]string, len(repositories))
+	for _, repository := range repositories {
+		var tags []string
+		next := fmt.Sprintf("/v2/%s/tags/list?n=%d", repository, registryPageSize)
+		for next != "" {
+			var page models.RegistryTags
+			var err error
+			if next, err = client.get(ctx, next, &page); err != nil {
+				fmt.Printf("[WARN] Tag request failed for %s: %v\n", repository, err)
+				break
+			}
+			tags = append(tags, page.Tags...)
+		}
+
+		catalog[repository] = tags
+		fmt.Printf("[INFO] Repository: %-45s Tags: %s\n", repository, strings.Join(tags, ", "))
+	}
+
+	data, err := json.MarshalIndent(catalog, "", "  ")
+	if err != nil {
+		return
+	}
+	path, err := saveLoot(data, "acr", name+".json")
+	if err != nil {
+		fmt.Printf("[WARN] Failed to save repository list for %s: %v\n", name, err)
+		return
+	}
+
+	fmt.Printf("[INFO] Repository list saved to %s\n", path)
+	fmt.Printf("[INFO] Pull images with: docker login %s -u %s, then docker pull %s/<repository>:<tag>\n", loginServer, username, loginServer)
+}
+
+// registryClient calls the Docker Registry v2 API of a registry with basic authentication
+type registryClient struct {
+	server   string
+	username string
+	password string
+}
+
+// get decodes the response for path into result and returns the path of the next page,
+// taken from the Link header, or "" on the last page
+func (c registryClient) get(ctx context.Context, path string, result interface{}) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://"+c.server+path, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.SetBasicAuth(c.username, c.password)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return "", fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return "", fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	match := linkNextPattern.FindStringSubmatch(resp.Header.Get("Link"))
+	if match == nil {
+		return "", nil
+	}
+
+	// The next link may be absolute or relative to the registry
+	next, err := url.Parse(match[1])
+	if err != nil {
+		return "", nil
+	}
+	return next.RequestURI(), nil
+}
@@ -0,0 +1,337 @@
+package management
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/f0rk3b0mb/GoCloudGhost/azure/models"
+)
+
+const (
+	sqlAPIVersion    = "2021-11-01"
+	cosmosAPIVersion = "2023-04-15"
+	redisAPIVersion  = "2023-08-01"
+)
+
+// enumerateSQLServers audits firewall rules, Entra ID administrators, auditing and network
+// access of every Azure SQL server and lists its databases
+func enumerateSQLServers(token, subscriptionID string) error {
+	ctx := context.Background()
+
+	url := fmt.Sprintf(
+		"https://management.azure.com/subscriptions/%s/providers/Microsoft.Sql/servers?api-version=%s",
+		subscriptionID,
+		sqlAPIVersion,
+	)
+
+	servers, err := listAllPages[models.SQLServer](ctx, token, url)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("\n=== SQL SERVERS ===")
+
+	if len(servers) == 0 {
+		fmt.Println("[INFO] No SQL servers found.")
+		return nil
+	}
+
+	var resources []models.Resource
+	for _, server := range servers {
+		props := server.Properties
+		fmt.Printf("\n[INFO] SQL Server: %-30s Resource Group: %-25s FQDN: %s\n",
+			server.Name,
+			extractResourceGroupFromID(server.ID),
+			props.FullyQualifiedDomainName,
+		)
+		fmt.Printf("       SQL admin login: %-20s Public network access: %-10s Minimal TLS: %s\n",
+			valueOr(props.AdministratorLogin, "<none>"),
+			valueOr(props.PublicNetworkAccess, "<unset>"),
+			valueOr(props.MinimalTLSVersion, "<unset>"),
+		)
+
+		resources = append(resources, models.Resource{
+			ID:       server.ID,
+			Name:     server.Name,
+			Type:     "Microsoft.Sql/servers",
+			Location: server.Location,
+		})
+
+		base := "https://management.azure.com" + server.ID
+		publicAccess := !strings.EqualFold(props.PublicNetworkAccess, "Disabled")
+
+		auditSQLFirewall(ctx, token, base, server.Name, publicAccess)
+		auditSQLAdministrators(ctx, token, base, server)
+		auditSQLAuditing(ctx, token, base, server.Name)
+		listSQLDatabases(ctx, token, base, server.Name)
+
+		switch props.MinimalTLSVersion {
+		case "1.2", "1.3":
+		default:
+			fmt.Println(models.Finding{Severity: models.SeverityLow, Resource: server.Name, Title: "Legacy TLS versions accepted", Evidence: "minimalTlsVersion=" + valueOr(props.MinimalTLSVersion, "<unset>")})
+		}
+	}
+
+	recordResources(resources...)
+
+	return nil
+}
+
+func auditSQLFirewall(ctx context.Context, token, base, serverName string, publicAccess bool) {
+	url := fmt.Sprintf("%s/firewallRules?api-version=%s", base, sqlAPIVersion)
+
+	rules, err := listAllPages[models.SQLFirewallRule](ctx, token, url)
+	if err != nil {
+		fmt.Printf("[WARN] Firewall rule request failed for %s: %v\n", serverName, err)
+		return
+	}
+
+	for _, rule := range rules {
+		start, end := rule.Properties.StartIPAddress, rule.Properties.EndIPAddress
+		fmt.Printf("       Firewall rule: %-35s %s - %s\n", rule.Name, start, end)
+
+		if !publicAccess {
+			continue
+		}
+
+		evidence := fmt.Sprintf("rule %s %s-%s", rule.Name, start, end)
+		switch {
+		case start == "0.0.0.0" && end == "255.255.255.255":
+			fmt.Println(models.Finding{Severity: models.SeverityHigh, Resource: serverName, Title: "SQL server open to the entire internet", Evidence: evidence})
+		case start == "0.0.0.0" && end == "0.0.0.0":
+			fmt.Println(models.Finding{Severity: models.SeverityMedium, Resource: serverName, Title: "Access allowed from all Azure services, including other tenants", Evidence: evidence})
+		}
+	}
+}
+
+func auditSQLAdministrators(ctx context.Context, token, base string, server models.SQLServer) {
+	url := fmt.Sprintf("%s/administrators?api-version=%s", base, sqlAPIVersion)
+
+	admins, err := listAllPages[models.SQLAdministrator](ctx, token, url)
+	if err != nil {
+		fmt.Printf("[WARN] Administrator request failed for %s: %v\n", server.Name, err)
+	}
+
+	for _, admin := range admins {
+		fmt.Printf("       Entra ID admin: %-30s Type: %-10s SID: %s\n",
+			admin.Properties.Login,
+			admin.Properties.AdministratorType,
+			admin.Properties.SID,
+		)
+	}
+	if err == nil && len(admins) == 0 {
+		fmt.Println(models.Finding{Severity: models.SeverityLow, Resource: server.Name, Title: "No Entra ID administrator configured", Evidence: "administrators=<none>"})
+	}
+
+	var adOnly models.SQLADOnlyAuthentication
+	adOnlyURL := fmt.Sprintf("%s/azureADOnlyAuthentications/Default?api-version=%s", base, sqlAPIVersion)
+	if err := makeAuthenticatedRequest(ctx, token, http.MethodGet, adOnlyURL, &adOnly); err != nil {
+		fmt.Printf("[WARN] Entra ID-only authentication request failed for %s: %v\n", server.Name, err)
+		return
+	}
+
+	if !adOnly.Properties.AzureADOnlyAuthentication {
+		fmt.Println(models.Finding{
+			Severity: models.SeverityMedium,
+			Resource: server.Name,
+			Title:    "SQL authentication enabled",
+			Evidence: "azureADOnlyAuthentication=false, administratorLogin=" + valueOr(server.Properties.AdministratorLogin, "<none>"),
+		})
+	}
+}
+
+func auditSQLAuditing(ctx context.Context, token, base, serverName string) {
+	url := fmt.Sprintf("%s/auditingSettings/default?api-version=%s", base, sqlAPIVersion)
+
+	var settings models.SQLAuditingSettings
+	if err := makeAuthenticatedRequest(ctx, token, http.MethodGet, url, &settings); err != nil {
+		fmt.Printf("[WARN] Auditing settings request failed for %s: %v\n", serverName, err)
+		return
+	}
+
+	props := settings.Properties
+	fmt.Printf("       Auditing: %-10s Azure Monitor: %-6t Storage: %s\n",
+		props.State,
+		props.IsAzureMonitorTargetEnabled,
+		valueOr(props.StorageEndpoint, "<none>"),
+	)
+
+	if !strings.EqualFold(props.State, "Enabled") {
+		fmt.Println(models.Finding{Severity: models.SeverityMedium, Resource: serverName, Title: "Server auditing disabled", Evidence: "auditingSettings.state=" + props.State})
+	}
+}
+
+func listSQLDatabases(ctx context.Context, token, base, serverName string) {
+	url := fmt.Sprintf("%s/databases?api-version=%s", base, sqlAPIVersion)
+
+	databases, err := listAllPages[models.SQLDatabase](ctx, token, url)
+	if err != nil {
+		fmt.Printf("[WARN] Database request failed for %s: %v\n", serverName, err)
+		return
+	}
+
+	for _, database := range databases {
+		if database.Name == "master" {
+			continue
+		}
+		sku := ""
+		if database.SKU != nil {
+			sku = database.SKU.Name
+		}
+		fmt.Printf("       Database: %-35s SKU: %-15s Status: %s\n", database.Name, sku, database.Properties.Status)
+	}
+}
+
+// enumerateCosmosDBAccounts audits Cosmos DB network and local auth settings and probes
+// listKeys and listConnectionStrings for account keys
+func enumerateCosmosDBAccounts(token, subscriptionID string) error {
+	ctx := context.Background()
+
+	url := fmt.Sprintf(
+		"https://management.azure.com/subscriptions/%s/providers/Microsoft.DocumentDB/databaseAccounts?api-version=%s",
+		subscriptionID,
+		cosmosAPIVersion,
+	)
+
+	accounts, err := listAllPages[models.CosmosDBAccount](ctx, token, url)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("\n=== COSMOS DB ACCOUNTS ===")
+
+	if len(accounts) == 0 {
+		fmt.Println("[INFO] No Cosmos DB accounts found.")
+		return nil
+	}
+
+	var resources []models.Resource
+	for _, account := range accounts {
+		props := account.Properties
+		fmt.Printf("\n[INFO] Cosmos DB: %-30s Resource Group: %-25s Kind: %-15s Endpoint: %s\n",
+			account.Name,
+			extractResourceGroupFromID(account.ID),
+			account.Kind,
+			props.DocumentEndpoint,
+		)
+
+		resources = append(resources, models.Resource{
+			ID:       account.ID,
+			Name:     account.Name,
+			Type:     "Microsoft.DocumentDB/databaseAccounts",
+			Location: account.Location,
+		})
+
+		if !strings.EqualFold(props.PublicNetworkAccess, "Disabled") && !props.IsVirtualNetworkFilterEnabled && len(props.IPRules) == 0 {
+			fmt.Println(models.Finding{Severity: models.SeverityMedium, Resource: account.Name, Title: "Reachable from all networks", Evidence: "publicNetworkAccess=" + valueOr(props.PublicNetworkAccess, "<unset>") + ", ipRules=<none>"})
+		}
+		if !props.DisableLocalAuth {
+			fmt.Println(models.Finding{Severity: models.SeverityMedium, Resource: account.Name, Title: "Key-based authentication enabled", Evidence: "disableLocalAuth=false"})
+		}
+
+		base := "https://management.azure.com" + account.ID
+
+		var keys models.CosmosDBKeys
+		keysURL := fmt.Sprintf("%s/listKeys?api-version=%s", base, cosmosAPIVersion)
+		if err := makeAuthenticatedRequest(ctx, token, http.MethodPost, keysURL, &keys); err != nil {
+			fmt.Printf("[WARN] Key request failed for %s: %v\n", account.Name, err)
+		} else {
+			printKey(account.Name, "primaryMasterKey", keys.PrimaryMasterKey)
+			printKey(account.Name, "secondaryMasterKey", keys.SecondaryMasterKey)
+			printKey(account.Name, "primaryReadonlyMasterKey", keys.PrimaryReadonlyMasterKey)
+			printKey(account.Name, "secondaryReadonlyMasterKey", keys.SecondaryReadonlyMasterKey)
+		}
+
+		var connections models.CosmosDBConnectionStrings
+		connectionsURL := fmt.Sprintf("%s/listConnectionStrings?api-version=%s", base, cosmosAPIVersion)
+		if err := makeAuthenticatedRequest(ctx, token, http.MethodPost, connectionsURL, &connections); err != nil {
+			fmt.Printf("[WARN] Connection string request failed for %s: %v\n", account.Name, err)
+			continue
+		}
+		for _, connection := range connections.ConnectionStrings {
+			printKey(account.Name, connection.Description, connection.ConnectionString)
+		}
+	}
+
+	recordResources(resources...)
+
+	return nil
+}
+
+// enumerateRedisCaches audits Redis transport and network settings and probes listKeys
+func enumerateRedisCaches(token, subscriptionID string) error {
+	ctx := context.Background()
+
+	url := fmt.Sprintf(
+		"https://management.azure.com/subscriptions/%s/providers/Microsoft.Cache/redis?api-version=%s",
+		subscriptionID,
+		redisAPIVersion,
+	)
+
+	caches, err := listAllPages[models.RedisCache](ctx, token, url)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("\n=== REDIS CACHES ===")
+
+	if len(caches) == 0 {
+		fmt.Println("[INFO] No Redis caches found.")
+		return nil
+	}
+
+	var resources []models.Resource
+	for _, cache := range caches {
+		props := cache.Properties
+		fmt.Printf("\n[INFO] Redis Cache: %-30s Resource Group: %-25s Host: %s:%d\n",
+			cache.Name,
+			extractResourceGroupFromID(cache.ID),
+			props.HostName,
+			props.SSLPort,
+		)
+
+		resources = append(resources, models.Resource{
+			ID:       cache.ID,
+			Name:     cache.Name,
+			Type:     "Microsoft.Cache/redis",
+			Location: cache.Location,
+		})
+
+		if props.EnableNonSSLPort {
+			fmt.Println(models.Finding{Severity: models.SeverityHigh, Resource: cache.Name, Title: "Unencrypted port enabled", Evidence: fmt.Sprintf("enableNonSslPort=true, port=%d", props.Port)})
+		}
+		if !strings.EqualFold(props.PublicNetworkAccess, "Disabled") {
+			fmt.Println(models.Finding{Severity: models.SeverityLow, Resource: cache.Name, Title: "Public network access enabled", Evidence: "publicNetworkAccess=" + valueOr(props.PublicNetworkAccess, "<unset>")})
+		}
+		if props.MinimumTLSVersion != "1.2" {
+			fmt.Println(models.Finding{Severity: models.SeverityLow, Resource: cache.Name, Title: "Legacy TLS versions accepted", Evidence: "minimumTlsVersion=" + valueOr(props.MinimumTLSVersion, "<unset>")})
+		}
+		if props.DisableAccessKeyAuthentication {
+			continue
+		}
+
+		var keys models.RedisKeys
+		keysURL := fmt.Sprintf("https://management.azure.com%s/listKeys?api-version=%s", cache.ID, redisAPIVersion)
+		if err := makeAuthenticatedRequest(ctx, token, http.MethodPost, keysURL, &keys); err != nil {
+			fmt.Printf("[WARN] Key request failed for %s: %v\n", cache.Name, err)
+			continue
+		}
+
+		printKey(cache.Name, "primaryKey", keys.PrimaryKey)
+		printKey(cache.Name, "secondaryKey", keys.SecondaryKey)
+	}
+
+	recordResources(resources...)
+
+	return nil
+}
+
+// printKey reports retrieved key material the same way harvested storage keys are reported
+func printKey(resource, name, value string) {
+	if value == "" {
+		return
+	}
+	fmt.Printf("[CRITICAL] Key accessible for %s: %s %s\n", resource, name, value)
+}
@@ -0,0 +1,85 @@
+package management
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/f0rk3b0mb/GoCloudGhost/azure/models"
+)
+
+const deploymentsAPIVersion = "2021-04-01"
+
+// deploymentScope is a subscription or resource group whose deployment history is mined
+type deploymentScope struct {
+	Name string
+	Path string
+}
+
+// enumerateDeployments exports the deployment history of the subscription and every
+// resource group in it and scans parameters, outputs and templates for secrets
+func enumerateDeployments(token, subscriptionID string) error {
+	ctx := context.Background()
+
+	groups, err := listResourceGroups(ctx, token, subscriptionID)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("\n=== DEPLOYMENTS ===")
+
+	scopes := []deploymentScope{
+		{Name: "subscription-" + subscriptionID, Path: "/subscriptions/" + subscriptionID},
+	}
+	for _, group := range groups {
+		scopes = append(scopes, deploymentScope{Name: group.Name, Path: group.ID})
+	}
+
+	for _, scope := range scopes {
+		url := fmt.Sprintf(
+			"https://management.azure.com%s/providers/Microsoft.Resources/deployments?api-version=%s",
+			scope.Path,
+			deploymentsAPIVersion,
+		)
+
+		deployments, err := listAllPages[models.Deployment](ctx, token, url)
+		if err != nil {
+			fmt.Printf("[WARN] Deployment request failed for %s: %v\n", scope.Name, err)
+			continue
+		}
+
+		for _, deployment := range deployments {
+			mineDeployment(ctx, token, scope.Name, deployment)
+		}
+	}
+
+	return nil
+}
+
+func mineDeployment(ctx context.Context, token, scopeName string, deployment models.Deployment) {
+	fmt.Printf("[INFO] Deployment: %-40s Scope: %-30s State: %-10s Time: %s\n",
+		deployment.Name,
+		scopeName,
+		deployment.Properties.ProvisioningState,
+		deployment.Properties.Timestamp,
+	)
+
+	exportURL := fmt.Sprintf(
+		"https://management.azure.com%s/exportTemplate?api-version=%s",
+		deployment.ID,
+		deploymentsAPIVersion,
+	)
+
+	var export models.DeploymentExport
+	if err := makeAuthenticatedRequest(ctx, token, http.MethodPost, exportURL, &export); err != nil {
+		fmt.Printf("[WARN] Template export failed for %s: %v\n", deployment.Name, err)
+	}
+
+	record := map[string]interface{}{
+		"parameters": deployment.Properties.Parameters,
+		"outputs":    deployment.Properties.Outputs,
+		"template":   export.Template,
+	}
+
+	saveAndScan(scopeName+"/"+deployment.Name, record, "deployments", scopeName, deployment.Name+".json")
+}
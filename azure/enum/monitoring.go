@@ -0,0 +1,358 @@
+package management
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/f0rk3b0mb/GoCloudGhost/azure/models"
+)
+
+const (
+	defenderAPIVersion        = "2024-01-01"
+	securityContactAPIVersion = "2020-01-01-preview"
+	diagnosticAPIVersion      = "2021-05-01-preview"
+	logProfileAPIVersion      = "2016-03-01"
+	resourceListAPIVersion    = "2021-04-01"
+)
+
+// defenderStandardTier is the pricing tier of an enabled Defender plan
+const defenderStandardTier = "Standard"
+
+// defenderPlans describes what each Defender for Cloud plan detects
+var defenderPlans = map[string]string{
+	"VirtualMachines":               "Defender for Servers: EDR and host threat detection on VMs",
+	"Arm":                           "Defender for Resource Manager: suspicious management operations",
+	"KeyVaults":                     "Defender for Key Vault: unusual secret and key access",
+	"StorageAccounts":               "Defender for Storage: anomalous data access and malware uploads",
+	"SqlServers":                    "Defender for Azure SQL: SQL injection and anomalous logins",
+	"SqlServerVirtualMachines":      "Defender for SQL on machines",
+	"OpenSourceRelationalDatabases": "Defender for open-source databases",
+	"CosmosDbs":                     "Defender for Cosmos DB: anomalous queries and key use",
+	"AppServices":                   "Defender for App Service: attacks on web apps",
+	"Containers":                    "Defender for Containers: Kubernetes and registry threats",
+	"Dns":                           "Defender for DNS: suspicious DNS queries",
+	"Api":                           "Defender for APIs",
+	"CloudPosture":                  "Defender CSPM: attack path analysis",
+}
+
+// diagnosticResourceTypes are the resources whose logs matter most for detection. Storage
+// account logs are configured on the blob service rather than the account.
+var diagnosticResourceTypes = []struct {
+	Type    string
+	SubPath string
+}{
+	{"Microsoft.KeyVault/vaults", ""},
+	{"Microsoft.Storage/storageAccounts", "/blobServices/default"},
+	{"Microsoft.Network/networkSecurityGroups", ""},
+	{"Microsoft.Network/azureFirewalls", ""},
+	{"Microsoft.Network/applicationGateways", ""},
+	{"Microsoft.Web/sites", ""},
+	{"Microsoft.ContainerRegistry/registries", ""},
+	{"Microsoft.Automation/automationAccounts", ""},
+	{"Microsoft.Logic/workflows", ""},
+}
+
+// monitoringCoverage collects what each check found for the final summary
+type monitoringCoverage struct {
+	enabledPlans  []string
+	disabledPlans []string
+	contacts      []string
+	activityLog   []string
+	// activityLogUnknown is set when a listing failed, so a missing export is not reported
+	activityLogUnknown bool
+	logged             int
+	unlogged           []string
+}
+
+// enumerateMonitoring reports Defender for Cloud plans, security contacts, diagnostic
+// settings on key resources and activity log exports, and summarizes detection coverage
+func enumerateMonitoring(token, subscriptionID string) error {
+	ctx := context.Background()
+	base := "https://management.azure.com/subscriptions/" + subscriptionID
+
+	var coverage monitoringCoverage
+
+	if err := enumerateDefenderPlans(ctx, token, base, &coverage); err != nil {
+		return err
+	}
+	enumerateSecurityContacts(ctx, token, base, &coverage)
+	enumerateActivityLogExport(ctx, token, base, &coverage)
+	enumerateDiagnosticSettings(ctx, token, base, &coverage)
+
+	printDetectionCoverage(coverage)
+
+	return nil
+}
+
+func enumerateDefenderPlans(ctx context.Context, token, base string, coverage *monitoringCoverage) error {
+	pricingsURL := fmt.Sprintf("%s/providers/Microsoft.Security/pricings?api-version=%s", base, defenderAPIVersion)
+
+	pricings, err := listAllPages[models.DefenderPricing](ctx, token, pricingsURL)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("\n=== DEFENDER FOR CLOUD PLANS ===")
+
+	sort.Slice(pricings, func(i, j int) bool { return pricings[i].Name < pricings[j].Name })
+
+	for _, pricing := range pricings {
+		fmt.Printf("[INFO] Plan: %-30s Tier: %-9s Sub-plan: %s\n",
+			pricing.Name,
+			pricing.Properties.PricingTier,
+			valueOr(pricing.Properties.SubPlan, "-"),
+		)
+
+		if strings.EqualFold(pricing.Properties.PricingTier, defenderStandardTier) {
+			coverage.enabledPlans = append(coverage.enabledPlans, pricing.Name)
+		} else {
+			coverage.disabledPlans = append(coverage.disabledPlans, pricing.Name)
+		}
+	}
+
+	return nil
+}
+
+func enumerateSecurityContacts(ctx context.Context, token, base string, coverage *monitoringCoverage) {
+	contactsURL := fmt.Sprintf("%s/providers/Microsoft.Security/securityContacts?api-version=%s", base, securityContactAPIVersion)
+
+	contacts, err := listAllPages[models.SecurityContact](ctx, token, contactsURL)
+	if err != nil {
+		fmt.Printf("[WARN] Security contact request failed: %v\n", err)
+		return
+	}
+
+	fmt.Println("\n=== SECURITY CONTACTS ===")
+
+	if len(contacts) == 0 {
+		fmt.Println("[INFO] No security contacts configured.")
+		return
+	}
+
+	for _, contact := range contacts {
+		props := contact.Properties
+		fmt.Printf("[INFO] Contact: %-15s Emails: %-40s Alerts: %-4s (min severity %s)  Notify roles: %s %s\n",
+			contact.Name,
+			valueOr(props.Emails, "<none>"),
+			props.AlertNotifications.State,
+			valueOr(props.AlertNotifications.MinimalSeverity, "-"),
+			props.NotificationsByRole.State,
+			strings.Join(props.NotificationsByRole.Roles, ","),
+		)
+
+		if strings.EqualFold(props.AlertNotifications.State, "On") {
+			recipients := props.Emails
+			if strings.EqualFold(props.NotificationsByRole.State, "On") && len(props.NotificationsByRole.Roles) > 0 {
+				recipients = strings.Trim(recipients+" + "+strings.Join(props.NotificationsByRole.Roles, ","), " +")
+			}
+			coverage.contacts = append(coverage.contacts, fmt.Sprintf("%s (min severity %s)", recipients, props.AlertNotifications.MinimalSeverity))
+		}
+	}
+}
+
+// enumerateActivityLogExport reports where the subscription's activity log is sent, through
+// subscription diagnostic settings or a legacy log profile
+func enumerateActivityLogExport(ctx context.Context, token, base string, coverage *monitoringCoverage) {
+	fmt.Println("\n=== ACTIVITY LOG EXPORT ===")
+
+	settings, err := listAllPages[models.DiagnosticSetting](ctx, token,
+		fmt.Sprintf("%s/providers/Microsoft.Insights/diagnosticSettings?api-version=%s", base, diagnosticAPIVersion))
+	if err != nil {
+		fmt.Printf("[WARN] Subscription diagnostic settings request failed: %v\n", err)
+		coverage.activityLogUnknown = true
+	}
+
+	for _, setting := range settings {
+		categories := enabledLogCategories(setting)
+		destinations := diagnosticDestinations(setting)
+		fmt.Printf("[INFO] Diagnostic setting: %-30s Categories: %-50s Destinations: %s\n",
+			setting.Name,
+			valueOr(strings.Join(categories, ","), "<none enabled>"),
+			strings.Join(destinations, ", "),
+		)
+
+		// A setting with every category disabled exports nothing
+		if len(categories) > 0 {
+			coverage.activityLog = append(coverage.activityLog, destinations...)
+		}
+	}
+
+	profiles, err := listAllPages[models.LogProfile](ctx, token,
+		fmt.Sprintf("%s/providers/Microsoft.Insights/logprofiles?api-version=%s", base, logProfileAPIVersion))
+	if err != nil {
+		fmt.Printf("[WARN] Log profile request failed: %v\n", err)
+		coverage.activityLogUnknown = true
+	}
+
+	for _, profile := range profiles {
+		var destinations []string
+		if profile.Properties.StorageAccountID != "" {
+			destinations = append(destinations, "storage "+extractNameFromID(profile.Properties.StorageAccountID))
+		}
+		if profile.Properties.ServiceBusRuleID != "" {
+			destinations = append(destinations, "event hub namespace "+serviceBusNamespace(profile.Properties.ServiceBusRuleID))
+		}
+		fmt.Printf("[INFO] Log profile: %-30s Categories: %-50s Destinations: %s\n",
+			profile.Name,
+			strings.Join(profile.Properties.Categories, ","),
+			strings.Join(destinations, ", "),
+		)
+		coverage.activityLog = append(coverage.activityLog, destinations...)
+	}
+
+	switch {
+	case len(settings) > 0 || len(profiles) > 0:
+	case coverage.activityLogUnknown:
+		fmt.Println("[WARN] Activity log export is unknown (request failed)")
+	default:
+		fmt.Println("[INFO] The activity log is not exported, it is only kept by Azure for 90 days.")
+	}
+}
+
+// enumerateDiagnosticSettings checks which key resources send their logs anywhere
+func enumerateDiagnosticSettings(ctx context.Context, token, base string, coverage *monitoringCoverage) {
+	fmt.Println("\n=== DIAGNOSTIC SETTINGS ===")
+
+	total := 0
+	for _, resourceType := range diagnosticResourceTypes {
+		listURL := fmt.Sprintf(
+			"%s/resources?api-version=%s&$filter=%s",
+			base,
+			resourceListAPIVersion,
+			url.QueryEscape("resourceType eq '"+resourceType.Type+"'"),
+		)
+
+		resources, err := listAllPages[models.Resource](ctx, token, listURL)
+		if err != nil {
+			fmt.Printf("[WARN] Failed to list %s: %v\n", resourceType.Type, err)
+			continue
+		}
+
+		for _, resource := range resources {
+			total++
+
+			settingsURL := fmt.Sprintf(
+				"https://management.azure.com%s%s/providers/Microsoft.Insights/diagnosticSettings?api-version=%s",
+				resource.ID,
+				resourceType.SubPath,
+				diagnosticAPIVersion,
+			)
+
+			settings, err := listAllPages[models.DiagnosticSetting](ctx, token, settingsURL)
+			if err != nil {
+				fmt.Printf("[WARN] Diagnostic settings request failed for %s: %v\n", resource.Name, err)
+				continue
+			}
+
+			var destinations []string
+			for _, setting := range settings {
+				if len(enabledLogCategories(setting)) > 0 {
+					destinations = append(destinations, diagnosticDestinations(setting)...)
+				}
+			}
+
+			label := extractNameFromID(resourceType.Type) + "/" + resource.Name
+			if len(destinations) == 0 {
+				fmt.Printf("[INFO] %-60s Logs: not collected\n", label)
+				coverage.unlogged = append(coverage.unlogged, label)
+				continue
+			}
+
+			fmt.Printf("[INFO] %-60s Logs: %s\n", label, strings.Join(destinations, ", "))
+			coverage.logged++
+		}
+	}
+
+	if total == 0 {
+		fmt.Println("[INFO] No key resources found.")
+	}
+}
+
+// enabledLogCategories returns the log categories or category groups a setting collects
+func enabledLogCategories(setting models.DiagnosticSetting) []string {
+	var categories []string
+	for _, log := range setting.Properties.Logs {
+		if !log.Enabled {
+			continue
+		}
+		categories = append(categories, valueOr(log.Category, log.CategoryGroup))
+	}
+	return categories
+}
+
+func diagnosticDestinations(setting models.DiagnosticSetting) []string {
+	props := setting.Properties
+	var destinations []string
+
+	if props.WorkspaceID != "" {
+		destinations = append(destinations, "workspace "+extractNameFromID(props.WorkspaceID))
+	}
+	if props.StorageAccountID != "" {
+		destinations = append(destinations, "storage "+extractNameFromID(props.StorageAccountID))
+	}
+	if props.EventHubAuthorizationRuleID != "" {
+		destinations = append(destinations, "event hub "+valueOr(props.EventHubName, serviceBusNamespace(props.EventHubAuthorizationRuleID)))
+	}
+	if props.MarketplacePartnerID != "" {
+		destinations = append(destinations, "partner "+extractNameFromID(props.MarketplacePartnerID))
+	}
+
+	return destinations
+}
+
+// serviceBusNamespace returns the namespace name from an event hub authorization rule ID
+func serviceBusNamespace(ruleID string) string {
+	parts := strings.Split(ruleID, "/")
+	for i, part := range parts {
+		if strings.EqualFold(part, "namespaces") && i+1 < len(parts) {
+			return parts[i+1]
+		}
+	}
+	return extractNameFromID(ruleID)
+}
+
+// printDetectionCoverage summarizes what will see activity in the subscription
+func printDetectionCoverage(coverage monitoringCoverage) {
+	fmt.Println("\n=== DETECTION COVERAGE ===")
+
+	fmt.Printf("[INFO] Defender for Cloud: %d of %d plans enabled\n",
+		len(coverage.enabledPlans),
+		len(coverage.enabledPlans)+len(coverage.disabledPlans),
+	)
+	for _, plan := range coverage.enabledPlans {
+		fmt.Printf("       Watching:     %s\n", planDescription(plan))
+	}
+	for _, plan := range coverage.disabledPlans {
+		fmt.Printf("       Not watching: %s\n", planDescription(plan))
+	}
+
+	if len(coverage.contacts) > 0 {
+		fmt.Printf("[INFO] Defender alerts are emailed to: %s\n", strings.Join(coverage.contacts, "; "))
+	} else {
+		fmt.Println("[INFO] Defender alert emails are not sent to any security contact")
+	}
+
+	switch {
+	case len(coverage.activityLog) > 0:
+		fmt.Printf("[INFO] Activity log exported to: %s\n", strings.Join(uniqueNames(coverage.activityLog), ", "))
+	case coverage.activityLogUnknown:
+		fmt.Println("[WARN] Activity log export: unknown (request failed)")
+	default:
+		fmt.Println("[INFO] Activity log is not exported to a SIEM or workspace")
+	}
+
+	fmt.Printf("[INFO] Key resources sending logs: %d of %d\n", coverage.logged, coverage.logged+len(coverage.unlogged))
+	for _, resource := range coverage.unlogged {
+		fmt.Printf("       No logs:      %s\n", resource)
+	}
+}
+
+func planDescription(plan string) string {
+	if description, ok := defenderPlans[plan]; ok {
+		return description
+	}
+	return plan
+}
@@ -0,0 +1,814 @@
+package management
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/f0rk3b0mb/GoCloudGhost/azure/models"
+)
+
+const networkAPIVersion = "2023-09-01"
+
+// managementPorts are the remote administration ports flagged when reachable from the internet
+var managementPorts = map[int]string{
+	22:   "SSH",
+	3389: "RDP",
+	5985: "WinRM",
+	5986: "WinRM over HTTPS",
+}
+
+// internetSources are the NSG and firewall source values that match any internet address
+var internetSources = map[string]bool{
+	"*":         true,
+	"any":       true,
+	"internet":  true,
+	"0.0.0.0/0": true,
+}
+
+// networkInventory holds the network resources of a subscription, indexed by lowercased ID
+// so references between them can be followed
+type networkInventory struct {
+	nsgs          []models.NetworkSecurityGroup
+	publicIPs     []models.PublicIPAddress
+	nics          []models.NetworkInterface
+	loadBalancers []models.LoadBalancer
+	appGateways   []models.ApplicationGateway
+	firewalls     []models.AzureFirewall
+
+	nsgByID        map[string]models.NetworkSecurityGroup
+	nsgBySubnet    map[string]models.NetworkSecurityGroup
+	publicIPByID   map[string]models.PublicIPAddress
+	nicByIPConfig  map[string]models.NetworkInterface
+	nicByPrivateIP map[string]models.NetworkInterface
+}
+
+// managementExposure is a management port of a VM that can be reached from the internet
+type managementExposure struct {
+	vm       string
+	port     int
+	endpoint string
+	path     string
+	evidence string
+}
+
+// enumerateNetwork collects NSGs, public IPs, load balancers, application gateways and
+// Azure Firewalls, then maps internet-reachable management ports to the VMs behind them
+func enumerateNetwork(token, subscriptionID string) error {
+	ctx := context.Background()
+	base := fmt.Sprintf("https://management.azure.com/subscriptions/%s/providers/Microsoft.Network", subscriptionID)
+
+	nsgs, err := listAllPages[models.NetworkSecurityGroup](ctx, token, networkURL(base, "networkSecurityGroups"))
+	if err != nil {
+		return err
+	}
+
+	inv := &networkInventory{
+		nsgs:          nsgs,
+		publicIPs:     listNetworkResources[models.PublicIPAddress](ctx, token, base, "publicIPAddresses"),
+		nics:          listNetworkResources[models.NetworkInterface](ctx, token, base, "networkInterfaces"),
+		loadBalancers: listNetworkResources[models.LoadBalancer](ctx, token, base, "loadBalancers"),
+		appGateways:   listNetworkResources[models.ApplicationGateway](ctx, token, base, "applicationGateways"),
+		firewalls:     listNetworkResources[models.AzureFirewall](ctx, token, base, "azureFirewalls"),
+	}
+	inv.index()
+
+	inv.printSecurityGroups()
+	inv.printPublicIPs()
+	exposures := inv.printLoadBalancers()
+	inv.printApplicationGateways()
+	exposures = append(exposures, inv.printFirewalls(ctx, token)...)
+	exposures = append(inv.directExposures(), exposures...)
+
+	reportManagementExposures(exposures)
+
+	return nil
+}
+
+func networkURL(base, kind string) string {
+	return fmt.Sprintf("%s/%s?api-version=%s", base, kind, networkAPIVersion)
+}
+
+// listNetworkResources lists one kind of network resource, warning instead of failing so a
+// missing permission on one kind does not hide the others
+func listNetworkResources[T any](ctx context.Context, token, base, kind string) []T {
+	items, err := listAllPages[T](ctx, token, networkURL(base, kind))
+	if err != nil {
+		fmt.Printf("[WARN] Failed to list %s: %v\n", kind, err)
+	}
+	return items
+}
+
+func (inv *networkInventory) index() {
+	inv.nsgByID = make(map[string]models.NetworkSecurityGroup)
+	inv.nsgBySubnet = make(map[string]models.NetworkSecurityGroup)
+	inv.publicIPByID = make(map[string]models.PublicIPAddress)
+	inv.nicByIPConfig = make(map[string]models.NetworkInterface)
+	inv.nicByPrivateIP = make(map[string]models.NetworkInterface)
+
+	for _, nsg := range inv.nsgs {
+		inv.nsgByID[strings.ToLower(nsg.ID)] = nsg
+		for _, subnet := range nsg.Properties.Subnets {
+			inv.nsgBySubnet[strings.ToLower(subnet.ID)] = nsg
+		}
+	}
+
+	for _, ip := range inv.publicIPs {
+		inv.publicIPByID[strings.ToLower(ip.ID)] = ip
+	}
+
+	for _, nic := range inv.nics {
+		for _, config := range nic.Properties.IPConfigurations {
+			inv.nicByIPConfig[strings.ToLower(config.ID)] = nic
+			if config.Properties.PrivateIPAddress != "" {
+				inv.nicByPrivateIP[config.Properties.PrivateIPAddress] = nic
+			}
+		}
+	}
+}
+
+func (inv *networkInventory) printSecurityGroups() {
+	fmt.Println("\n=== NETWORK SECURITY GROUPS ===")
+
+	if len(inv.nsgs) == 0 {
+		fmt.Println("[INFO] No network security groups found.")
+		return
+	}
+
+	for _, nsg := range inv.nsgs {
+		fmt.Printf("\n[INFO] NSG: %-30s Resource Group: %-25s NICs: %-3d Subnets: %d\n",
+			nsg.Name,
+			extractResourceGroupFromID(nsg.ID),
+			len(nsg.Properties.NetworkInterfaces),
+			len(nsg.Properties.Subnets),
+		)
+
+		for _, rule := range inboundRules(nsg) {
+			props := rule.Properties
+			fmt.Printf("       %-6d %-35s %-6s %-5s from %-25s ports %s\n",
+				props.Priority,
+				rule.Name,
+				props.Access,
+				props.Protocol,
+				strings.Join(ruleSources(props), ","),
+				strings.Join(rulePorts(props), ","),
+			)
+
+			if finding, ok := auditSecurityRule(nsg.Name, rule); ok {
+				fmt.Println(finding)
+			}
+		}
+	}
+}
+
+// inboundRules returns the custom and default inbound rules of an NSG in the order they
+// are evaluated
+func inboundRules(nsg models.NetworkSecurityGroup) []models.SecurityRule {
+	var rules []models.SecurityRule
+	for _, rule := range append(append([]models.SecurityRule(nil), nsg.Properties.SecurityRules...), nsg.Properties.DefaultSecurityRules...) {
+		if strings.EqualFold(rule.Properties.Direction, "Inbound") {
+			rules = append(rules, rule)
+		}
+	}
+
+	sort.SliceStable(rules, func(i, j int) bool {
+		return rules[i].Properties.Priority < rules[j].Properties.Priority
+	})
+	return rules
+}
+
+// auditSecurityRule flags custom rules that allow internet traffic to every port or to a
+// management port
+func auditSecurityRule(nsgName string, rule models.SecurityRule) (models.Finding, bool) {
+	props := rule.Properties
+	if !strings.EqualFold(props.Access, "Allow") || !anyInternetSource(ruleSources(props)) || !tcpProtocol(props.Protocol) {
+		return models.Finding{}, false
+	}
+
+	evidence := fmt.Sprintf("rule %s (priority %d) ports %s", rule.Name, props.Priority, strings.Join(rulePorts(props), ","))
+
+	for _, port := range rulePorts(props) {
+		if port == "*" || port == "0-65535" {
+			return models.Finding{Severity: models.SeverityHigh, Resource: nsgName, Title: "All ports open to the internet", Evidence: evidence}, true
+		}
+	}
+
+	var exposed []string
+	for _, port := range sortedManagementPorts() {
+		if portsCover(rulePorts(props), port) {
+			exposed = append(exposed, managementPorts[port])
+		}
+	}
+	if len(exposed) > 0 {
+		return models.Finding{
+			Severity: models.SeverityMedium,
+			Resource: nsgName,
+			Title:    "Management port allowed from the internet (" + strings.Join(uniqueNames(exposed), ", ") + ")",
+			Evidence: evidence,
+		}, true
+	}
+
+	return models.Finding{}, false
+}
+
+func (inv *networkInventory) printPublicIPs() {
+	fmt.Println("\n=== PUBLIC IP ADDRESSES ===")
+
+	if len(inv.publicIPs) == 0 {
+		fmt.Println("[INFO] No public IP addresses found.")
+		return
+	}
+
+	for _, ip := range inv.publicIPs {
+		attached := "<unattached>"
+		if ip.Properties.IPConfiguration != nil {
+			attached = inv.describeIPConfiguration(ip.Properties.IPConfiguration.ID)
+		}
+
+		fqdn := ""
+		if ip.Properties.DNSSettings != nil {
+			fqdn = ip.Properties.DNSSettings.FQDN
+		}
+
+		fmt.Printf("[INFO] Public IP: %-16s Name: %-30s SKU: %-9s FQDN: %-45s Attached: %s\n",
+			valueOr(ip.Properties.IPAddress, ip.Properties.PublicIPAllocationMethod),
+			ip.Name,
+			skuName(ip.SKU),
+			fqdn,
+			attached,
+		)
+	}
+}
+
+// describeIPConfiguration names the resource an IP configuration belongs to, preferring
+// the VM behind a NIC
+func (inv *networkInventory) describeIPConfiguration(id string) string {
+	if nic, ok := inv.nicByIPConfig[strings.ToLower(id)]; ok {
+		return describeNIC(nic)
+	}
+
+	parts := strings.Split(id, "/")
+	for i, part := range parts {
+		if strings.EqualFold(part, "providers") && i+3 < len(parts) {
+			return parts[i+2] + " " + parts[i+3]
+		}
+	}
+	return id
+}
+
+func describeNIC(nic models.NetworkInterface) string {
+	if nic.Properties.VirtualMachine != nil {
+		return fmt.Sprintf("VM %s (NIC %s)", extractNameFromID(nic.Properties.VirtualMachine.ID), nic.Name)
+	}
+	return "NIC " + nic.Name
+}
+
+// publicAddress resolves a public IP reference to its address
+func (inv *networkInventory) publicAddress(ref *models.SubResource) (models.PublicIPAddress, bool) {
+	if ref == nil {
+		return models.PublicIPAddress{}, false
+	}
+	ip, ok := inv.publicIPByID[strings.ToLower(ref.ID)]
+	return ip, ok
+}
+
+// printLoadBalancers lists the frontends and rules of every load balancer and returns the
+// management ports its public frontends forward to VMs
+func (inv *networkInventory) printLoadBalancers() []managementExposure {
+	fmt.Println("\n=== LOAD BALANCERS ===")
+
+	if len(inv.loadBalancers) == 0 {
+		fmt.Println("[INFO] No load balancers found.")
+		return nil
+	}
+
+	var exposures []managementExposure
+
+	for _, lb := range inv.loadBalancers {
+		fmt.Printf("\n[INFO] Load Balancer: %-30s Resource Group: %-25s SKU: %s\n",
+			lb.Name,
+			extractResourceGroupFromID(lb.ID),
+			skuName(lb.SKU),
+		)
+
+		frontends := make(map[string]string)
+		for _, frontend := range lb.Properties.FrontendIPConfigurations {
+			address := frontend.Properties.PrivateIPAddress + " (private)"
+			if ip, ok := inv.publicAddress(frontend.Properties.PublicIPAddress); ok {
+				address = ip.Properties.IPAddress
+				frontends[strings.ToLower(frontend.ID)] = ip.Properties.IPAddress
+			}
+			fmt.Printf("       Frontend %-25s %s\n", frontend.Name, address)
+		}
+
+		pools := make(map[string][]models.SubResource)
+		for _, pool := range lb.Properties.BackendAddressPools {
+			pools[strings.ToLower(pool.ID)] = pool.Properties.BackendIPConfigurations
+		}
+
+		standard := strings.EqualFold(skuName(lb.SKU), "Standard")
+
+		for _, rule := range append(append([]models.LoadBalancerRule(nil), lb.Properties.LoadBalancingRules...), lb.Properties.InboundNatRules...) {
+			props := rule.Properties
+			fmt.Printf("       Rule     %-25s %-4s %d -> %d\n", rule.Name, props.Protocol, props.FrontendPort, props.BackendPort)
+
+			if props.FrontendIPConfiguration == nil || !tcpProtocol(props.Protocol) {
+				continue
+			}
+			address, public := frontends[strings.ToLower(props.FrontendIPConfiguration.ID)]
+			if _, management := managementPorts[props.BackendPort]; !public || !management {
+				continue
+			}
+
+			var backends []models.SubResource
+			if props.BackendIPConfiguration != nil {
+				backends = append(backends, *props.BackendIPConfiguration)
+			}
+			if props.BackendAddressPool != nil {
+				backends = append(backends, pools[strings.ToLower(props.BackendAddressPool.ID)]...)
+			}
+
+			for _, backend := range backends {
+				nic, ok := inv.nicByIPConfig[strings.ToLower(backend.ID)]
+				if !ok {
+					continue
+				}
+				allowed, evidence := inv.inboundAllowed(nic, backend.ID, props.BackendPort, standard)
+				if !allowed {
+					continue
+				}
+				exposures = append(exposures, managementExposure{
+					vm:       describeNIC(nic),
+					port:     props.BackendPort,
+					endpoint: fmt.Sprintf("%s:%d", address, props.FrontendPort),
+					path:     "load balancer " + lb.Name + " rule " + rule.Name,
+					evidence: evidence,
+				})
+			}
+		}
+	}
+
+	return exposures
+}
+
+func (inv *networkInventory) printApplicationGateways() {
+	fmt.Println("\n=== APPLICATION GATEWAYS ===")
+
+	if len(inv.appGateways) == 0 {
+		fmt.Println("[INFO] No application gateways found.")
+		return
+	}
+
+	for _, gateway := range inv.appGateways {
+		props := gateway.Properties
+		waf := wafState(props)
+
+		fmt.Printf("\n[INFO] Application Gateway: %-30s Resource Group: %-25s SKU: %-15s WAF: %s\n",
+			gateway.Name,
+			extractResourceGroupFromID(gateway.ID),
+			skuName(props.SKU),
+			waf,
+		)
+
+		frontends := make(map[string]string)
+		for _, frontend := range props.FrontendIPConfigurations {
+			address := frontend.Properties.PrivateIPAddress
+			if ip, ok := inv.publicAddress(frontend.Properties.PublicIPAddress); ok {
+				address = ip.Properties.IPAddress
+			}
+			frontends[strings.ToLower(frontend.ID)] = address
+		}
+
+		ports := make(map[string]int)
+		for _, port := range props.FrontendPorts {
+			ports[strings.ToLower(port.ID)] = port.Properties.Port
+		}
+
+		public := false
+		for _, listener := range props.HTTPListeners {
+			address, port := "", 0
+			if listener.Properties.FrontendIPConfiguration != nil {
+				address = frontends[strings.ToLower(listener.Properties.FrontendIPConfiguration.ID)]
+				for _, frontend := range props.FrontendIPConfigurations {
+					if strings.EqualFold(frontend.ID, listener.Properties.FrontendIPConfiguration.ID) && frontend.Properties.PublicIPAddress != nil {
+						public = true
+					}
+				}
+			}
+			if listener.Properties.FrontendPort != nil {
+				port = ports[strings.ToLower(listener.Properties.FrontendPort.ID)]
+			}
+
+			hosts := append([]string{listener.Properties.HostName}, listener.Properties.HostNames...)
+			fmt.Printf("       Listener %-25s %-5s %s:%d %s\n",
+				listener.Name,
+				listener.Properties.Protocol,
+				address,
+				port,
+				strings.Join(nonEmpty(hosts), ","),
+			)
+		}
+
+		switch {
+		case public && waf == "disabled":
+			fmt.Println(models.Finding{Severity: models.SeverityMedium, Resource: gateway.Name, Title: "Internet-facing application gateway without WAF", Evidence: "sku=" + skuName(props.SKU)})
+		case public && waf == "Detection":
+			fmt.Println(models.Finding{Severity: models.SeverityLow, Resource: gateway.Name, Title: "WAF only detects, does not block", Evidence: "firewallMode=Detection"})
+		}
+	}
+}
+
+// wafState reports whether the gateway has a WAF and, for classic WAF configuration, its mode
+func wafState(props models.ApplicationGatewayProperties) string {
+	if props.FirewallPolicy != nil {
+		return "policy " + extractNameFromID(props.FirewallPolicy.ID)
+	}
+	if props.WebApplicationFirewallConfiguration != nil && props.WebApplicationFirewallConfiguration.Enabled {
+		return props.WebApplicationFirewallConfiguration.FirewallMode
+	}
+	return "disabled"
+}
+
+// printFirewalls lists the classic and policy rules of every Azure Firewall and returns the
+// management ports its DNAT rules publish to VMs
+func (inv *networkInventory) printFirewalls(ctx context.Context, token string) []managementExposure {
+	fmt.Println("\n=== AZURE FIREWALLS ===")
+
+	if len(inv.firewalls) == 0 {
+		fmt.Println("[INFO] No Azure Firewalls found.")
+		return nil
+	}
+
+	var exposures []managementExposure
+
+	for _, firewall := range inv.firewalls {
+		props := firewall.Properties
+		fmt.Printf("\n[INFO] Azure Firewall: %-30s Resource Group: %s\n", firewall.Name, extractResourceGroupFromID(firewall.ID))
+
+		for _, config := range props.IPConfigurations {
+			address := config.Properties.PrivateIPAddress + " (private)"
+			if ip, ok := inv.publicAddress(config.Properties.PublicIPAddress); ok {
+				address = ip.Properties.IPAddress + " (public), " + address
+			}
+			fmt.Printf("       Address  %s\n", address)
+		}
+
+		var rules []models.FirewallPolicyRule
+
+		for _, collection := range props.NatRuleCollections {
+			for _, rule := range collection.Properties.Rules {
+				rules = append(rules, classicFirewallRule(rule, "NatRule"))
+			}
+		}
+		for _, collection := range props.NetworkRuleCollections {
+			if !strings.EqualFold(collection.Properties.Action.Type, "Allow") {
+				continue
+			}
+			for _, rule := range collection.Properties.Rules {
+				rules = append(rules, classicFirewallRule(rule, "NetworkRule"))
+			}
+		}
+		for _, collection := range props.ApplicationRuleCollections {
+			if !strings.EqualFold(collection.Properties.Action.Type, "Allow") {
+				continue
+			}
+			for _, rule := range collection.Properties.Rules {
+				rules = append(rules, models.FirewallPolicyRule{
+					Name:            rule.Name,
+					RuleType:        "ApplicationRule",
+					Protocols:       rule.Protocols,
+					SourceAddresses: rule.SourceAddresses,
+					TargetFQDNs:     rule.TargetFQDNs,
+				})
+			}
+		}
+
+		if props.FirewallPolicy != nil {
+			fmt.Printf("       Policy   %s\n", extractNameFromID(props.FirewallPolicy.ID))
+			rules = append(rules, listFirewallPolicyRules(ctx, token, props.FirewallPolicy.ID)...)
+		}
+
+		for _, rule := range rules {
+			printFirewallRule(rule)
+
+			if finding, ok := auditFirewallRule(firewall.Name, rule); ok {
+				fmt.Println(finding)
+			}
+
+			exposures = append(exposures, inv.dnatExposures(firewall.Name, rule)...)
+		}
+	}
+
+	return exposures
+}
+
+func classicFirewallRule(rule models.FirewallNetworkRule, ruleType string) models.FirewallPolicyRule {
+	return models.FirewallPolicyRule{
+		Name:                 rule.Name,
+		RuleType:             ruleType,
+		IPProtocols:          rule.Protocols,
+		SourceAddresses:      rule.SourceAddresses,
+		DestinationAddresses: rule.DestinationAddresses,
+		DestinationPorts:     rule.DestinationPorts,
+		TranslatedAddress:    rule.TranslatedAddress,
+		TranslatedPort:       rule.TranslatedPort,
+	}
+}
+
+// listFirewallPolicyRules returns the rules of the allow and DNAT collections of a firewall policy
+func listFirewallPolicyRules(ctx context.Context, token, policyID string) []models.FirewallPolicyRule {
+	url := fmt.Sprintf("https://management.azure.com%s/ruleCollectionGroups?api-version=%s", policyID, networkAPIVersion)
+
+	groups, err := listAllPages[models.FirewallPolicyRuleCollectionGroup](ctx, token, url)
+	if err != nil {
+		fmt.Printf("[WARN] Rule collection group request failed for %s: %v\n", extractNameFromID(policyID), err)
+		return nil
+	}
+
+	var rules []models.FirewallPolicyRule
+	for _, group := range groups {
+		for _, collection := range group.Properties.RuleCollections {
+			if strings.EqualFold(collection.Action.Type, "Deny") {
+				continue
+			}
+			rules = append(rules, collection.Rules...)
+		}
+	}
+	return rules
+}
+
+func printFirewallRule(rule models.FirewallPolicyRule) {
+	switch rule.RuleType {
+	case "NatRule":
+		fmt.Printf("       DNAT     %-25s from %-20s to %s:%s -> %s:%s\n",
+			rule.Name,
+			strings.Join(rule.SourceAddresses, ","),
+			strings.Join(rule.DestinationAddresses, ","),
+			strings.Join(rule.DestinationPorts, ","),
+			rule.TranslatedAddress,
+			rule.TranslatedPort,
+		)
+	case "ApplicationRule":
+		var protocols []string
+		for _, protocol := range rule.Protocols {
+			protocols = append(protocols, fmt.Sprintf("%s:%d", protocol.ProtocolType, protocol.Port))
+		}
+		fmt.Printf("       App      %-25s from %-20s to %s (%s)\n",
+			rule.Name,
+			strings.Join(rule.SourceAddresses, ","),
+			strings.Join(rule.TargetFQDNs, ","),
+			strings.Join(protocols, ","),
+		)
+	default:
+		fmt.Printf("       Network  %-25s from %-20s to %s ports %s (%s)\n",
+			rule.Name,
+			strings.Join(rule.SourceAddresses, ","),
+			strings.Join(rule.DestinationAddresses, ","),
+			strings.Join(rule.DestinationPorts, ","),
+			strings.Join(rule.IPProtocols, ","),
+		)
+	}
+}
+
+// auditFirewallRule flags network rules that allow any source to any destination on any port
+func auditFirewallRule(firewallName string, rule models.FirewallPolicyRule) (models.Finding, bool) {
+	if rule.RuleType != "NetworkRule" {
+		return models.Finding{}, false
+	}
+
+	if anyInternetSource(rule.SourceAddresses) && anyInternetSource(rule.DestinationAddresses) && portsCoverAll(rule.DestinationPorts) {
+		return models.Finding{
+			Severity: models.SeverityMedium,
+			Resource: firewallName,
+			Title:    "Firewall allows any source to any destination",
+			Evidence: "rule " + rule.Name,
+		}, true
+	}
+	return models.Finding{}, false
+}
+
+// dnatExposures maps a DNAT rule that publishes a management port to the internet to the
+// VM owning the translated address
+func (inv *networkInventory) dnatExposures(firewallName string, rule models.FirewallPolicyRule) []managementExposure {
+	if rule.RuleType != "NatRule" || !anyInternetSource(rule.SourceAddresses) {
+		return nil
+	}
+
+	port, err := strconv.Atoi(rule.TranslatedPort)
+	if _, management := managementPorts[port]; err != nil || !management {
+		return nil
+	}
+
+	target := "host " + rule.TranslatedAddress
+	if nic, ok := inv.nicByPrivateIP[rule.TranslatedAddress]; ok {
+		target = describeNIC(nic)
+	}
+
+	// DNAT traffic reaches the VM from the firewall's private address, so the VM's NSGs
+	// see virtual network traffic and do not filter the internet source
+	return []managementExposure{{
+		vm:       target,
+		port:     port,
+		endpoint: strings.Join(rule.DestinationAddresses, ",") + ":" + strings.Join(rule.DestinationPorts, ","),
+		path:     "firewall " + firewallName + " DNAT rule " + rule.Name,
+		evidence: "translated to " + rule.TranslatedAddress + ":" + rule.TranslatedPort,
+	}}
+}
+
+// directExposures returns the management ports of VMs with a public IP on their NIC that
+// their NSGs let through
+func (inv *networkInventory) directExposures() []managementExposure {
+	var exposures []managementExposure
+
+	for _, nic := range inv.nics {
+		for _, config := range nic.Properties.IPConfigurations {
+			ip, ok := inv.publicAddress(config.Properties.PublicIPAddress)
+			if !ok {
+				continue
+			}
+			standard := strings.EqualFold(skuName(ip.SKU), "Standard")
+
+			for _, port := range sortedManagementPorts() {
+				allowed, evidence := inv.inboundAllowed(nic, config.ID, port, standard)
+				if !allowed {
+					continue
+				}
+				exposures = append(exposures, managementExposure{
+					vm:       describeNIC(nic),
+					port:     port,
+					endpoint: fmt.Sprintf("%s:%d", ip.Properties.IPAddress, port),
+					path:     "public IP " + ip.Name,
+					evidence: evidence,
+				})
+			}
+		}
+	}
+
+	return exposures
+}
+
+// inboundAllowed evaluates the subnet and NIC NSGs in front of an IP configuration for TCP
+// traffic from the internet to port. Without any NSG, Basic SKU frontends are open and
+// Standard SKU frontends are closed by default.
+func (inv *networkInventory) inboundAllowed(nic models.NetworkInterface, ipConfigID string, port int, standardSKU bool) (bool, string) {
+	var nsgs []models.NetworkSecurityGroup
+
+	for _, config := range nic.Properties.IPConfigurations {
+		if strings.EqualFold(config.ID, ipConfigID) && config.Properties.Subnet != nil {
+			if nsg, ok := inv.nsgBySubnet[strings.ToLower(config.Properties.Subnet.ID)]; ok {
+				nsgs = append(nsgs, nsg)
+			}
+		}
+	}
+	if nic.Properties.NetworkSecurityGroup != nil {
+		if nsg, ok := inv.nsgByID[strings.ToLower(nic.Properties.NetworkSecurityGroup.ID)]; ok {
+			nsgs = append(nsgs, nsg)
+		}
+	}
+
+	if len(nsgs) == 0 {
+		return !standardSKU, "no NSG on NIC or subnet"
+	}
+
+	var allowedBy []string
+	for _, nsg := range nsgs {
+		rule, allowed := evaluateInbound(nsg, port)
+		if !allowed {
+			return false, ""
+		}
+		allowedBy = append(allowedBy, nsg.Name+"/"+rule)
+	}
+
+	return true, "allowed by " + strings.Join(allowedBy, ", ")
+}
+
+// evaluateInbound returns the first rule of the NSG that matches TCP traffic from the
+// internet to port, and whether that rule allows it
+func evaluateInbound(nsg models.NetworkSecurityGroup, port int) (string, bool) {
+	for _, rule := range inboundRules(nsg) {
+		props := rule.Properties
+		if !tcpProtocol(props.Protocol) || !anyInternetSource(ruleSources(props)) || !portsCover(rulePorts(props), port) {
+			continue
+		}
+		return rule.Name, strings.EqualFold(props.Access, "Allow")
+	}
+	return "", false
+}
+
+func reportManagementExposures(exposures []managementExposure) {
+	fmt.Println("\n=== INTERNET-EXPOSED MANAGEMENT PORTS ===")
+
+	if len(exposures) == 0 {
+		fmt.Println("[INFO] No internet-exposed management ports found.")
+		return
+	}
+
+	for _, exposure := range exposures {
+		fmt.Println(models.Finding{
+			Severity: models.SeverityHigh,
+			Resource: exposure.vm,
+			Title:    managementPorts[exposure.port] + " reachable from the internet",
+			Evidence: fmt.Sprintf("%s via %s; %s", exposure.endpoint, exposure.path, exposure.evidence),
+		})
+	}
+}
+
+func ruleSources(props models.SecurityRuleProperties) []string {
+	return nonEmpty(append([]string{props.SourceAddressPrefix}, props.SourceAddressPrefixes...))
+}
+
+func rulePorts(props models.SecurityRuleProperties) []string {
+	return nonEmpty(append([]string{props.DestinationPortRange}, props.DestinationPortRanges...))
+}
+
+func anyInternetSource(sources []string) bool {
+	for _, source := range sources {
+		if internetSources[strings.ToLower(source)] {
+			return true
+		}
+	}
+	return false
+}
+
+func tcpProtocol(protocol string) bool {
+	switch strings.ToLower(protocol) {
+	case "*", "any", "tcp", "all":
+		return true
+	}
+	return false
+}
+
+// portsCover reports whether any of the port ranges ("*", "22" or "20-25") contains port
+func portsCover(ranges []string, port int) bool {
+	for _, r := range ranges {
+		if r == "*" {
+			return true
+		}
+		low, high, found := strings.Cut(r, "-")
+		if !found {
+			high = low
+		}
+		from, errFrom := strconv.Atoi(strings.TrimSpace(low))
+		to, errTo := strconv.Atoi(strings.TrimSpace(high))
+		if errFrom == nil && errTo == nil && port >= from && port <= to {
+			return true
+		}
+	}
+	return false
+}
+
+func portsCoverAll(ranges []string) bool {
+	for _, r := range ranges {
+		if r == "*" || r == "0-65535" || r == "1-65535" {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedManagementPorts() []int {
+	ports := make([]int, 0, len(managementPorts))
+	for port := range managementPorts {
+		ports = append(ports, port)
+	}
+	sort.Ints(ports)
+	return ports
+}
+
+func uniqueNames(names []string) []string {
+	seen := make(map[string]bool)
+	var unique []string
+	for _, name := range names {
+		if !seen[name] {
+			seen[name] = true
+			unique = append(unique, name)
+		}
+	}
+	return unique
+}
+
+func nonEmpty(values []string) []string {
+	var result []string
+	for _, value := range values {
+		if value != "" {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+func skuName(sku *models.SKU) string {
+	if sku == nil || sku.Name == "" {
+		return "Basic"
+	}
+	return sku.Name
+}
+
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,169 @@
+package management
+
+import (
+	"testing"
+
+	"github.com/f0rk3b0mb/GoCloudGhost/azure/models"
+)
+
+func TestPortsCover(t *testing.T) {
+	tests := []struct {
+		ranges []string
+		port   int
+		want   bool
+	}{
+		{[]string{"*"}, 22, true},
+		{[]string{"22"}, 22, true},
+		{[]string{"23"}, 22, false},
+		{[]string{"20-25"}, 22, true},
+		{[]string{"20-22"}, 22, true},
+		{[]string{"22-25"}, 22, true},
+		{[]string{"23-25"}, 22, false},
+		{[]string{"3000-3388"}, 3389, false},
+		{[]string{"3390-4000"}, 3389, false},
+		{[]string{"80", "443", "3389"}, 3389, true},
+		{[]string{"0-65535"}, 5986, true},
+		{[]string{" 20 - 25 "}, 22, true},
+		{[]string{"ssh"}, 22, false},
+		{nil, 22, false},
+	}
+
+	for _, tt := range tests {
+		if got := portsCover(tt.ranges, tt.port); got != tt.want {
+			t.Errorf("portsCover(%q, %d) = %v, want %v", tt.ranges, tt.port, got, tt.want)
+		}
+	}
+}
+
+// securityRule builds an inbound TCP rule
+func securityRule(name string, priority int, access, source, ports string) models.SecurityRule {
+	return models.SecurityRule{
+		Name: name,
+		Properties: models.SecurityRuleProperties{
+			Protocol:             "Tcp",
+			SourceAddressPrefix:  source,
+			DestinationPortRange: ports,
+			Access:               access,
+			Priority:             priority,
+			Direction:            "Inbound",
+		},
+	}
+}
+
+func TestEvaluateInbound(t *testing.T) {
+	denyAll := securityRule("DenyAllInBound", 65500, "Deny", "*", "*")
+	allowVNet := securityRule("AllowVnetInBound", 65000, "Allow", "VirtualNetwork", "*")
+
+	tests := []struct {
+		name        string
+		rules       []models.SecurityRule
+		port        int
+		wantRule    string
+		wantAllowed bool
+	}{
+		{
+			name:     "default rules deny the internet",
+			port:     22,
+			wantRule: "DenyAllInBound",
+		},
+		{
+			name:        "allow from any source",
+			rules:       []models.SecurityRule{securityRule("ssh", 100, "Allow", "*", "22")},
+			port:        22,
+			wantRule:    "ssh",
+			wantAllowed: true,
+		},
+		{
+			name:        "allow from the Internet tag",
+			rules:       []models.SecurityRule{securityRule("rdp", 100, "Allow", "Internet", "3389")},
+			port:        3389,
+			wantRule:    "rdp",
+			wantAllowed: true,
+		},
+		{
+			name:        "allow over a port range",
+			rules:       []models.SecurityRule{securityRule("winrm", 100, "Allow", "0.0.0.0/0", "5985-5986")},
+			port:        5986,
+			wantRule:    "winrm",
+			wantAllowed: true,
+		},
+		{
+			name: "lower priority number wins",
+			rules: []models.SecurityRule{
+				securityRule("allow-ssh", 200, "Allow", "*", "22"),
+				securityRule("deny-ssh", 100, "Deny", "*", "22"),
+			},
+			port:     22,
+			wantRule: "deny-ssh",
+		},
+		{
+			name: "allow evaluated before a later deny",
+			rules: []models.SecurityRule{
+				securityRule("allow-ssh", 100, "Allow", "*", "22"),
+				securityRule("deny-ssh", 200, "Deny", "*", "22"),
+			},
+			port:        22,
+			wantRule:    "allow-ssh",
+			wantAllowed: true,
+		},
+		{
+			name:     "restricted source does not match",
+			rules:    []models.SecurityRule{securityRule("office", 100, "Allow", "203.0.113.0/24", "22")},
+			port:     22,
+			wantRule: "DenyAllInBound",
+		},
+		{
+			name:     "other port does not match",
+			rules:    []models.SecurityRule{securityRule("https", 100, "Allow", "*", "443")},
+			port:     22,
+			wantRule: "DenyAllInBound",
+		},
+		{
+			name: "udp does not expose tcp ports",
+			rules: []models.SecurityRule{func() models.SecurityRule {
+				rule := securityRule("udp", 100, "Allow", "*", "3389")
+				rule.Properties.Protocol = "Udp"
+				return rule
+			}()},
+			port:     3389,
+			wantRule: "DenyAllInBound",
+		},
+		{
+			name: "outbound rules are ignored",
+			rules: []models.SecurityRule{func() models.SecurityRule {
+				rule := securityRule("outbound", 100, "Allow", "*", "22")
+				rule.Properties.Direction = "Outbound"
+				return rule
+			}()},
+			port:     22,
+			wantRule: "DenyAllInBound",
+		},
+		{
+			name: "source in a prefix list",
+			rules: []models.SecurityRule{func() models.SecurityRule {
+				rule := securityRule("list", 100, "Allow", "", "22")
+				rule.Properties.SourceAddressPrefixes = []string{"10.0.0.0/8", "0.0.0.0/0"}
+				return rule
+			}()},
+			port:        22,
+			wantRule:    "list",
+			wantAllowed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nsg := models.NetworkSecurityGroup{
+				Properties: models.NetworkSecurityGroupProperties{
+					SecurityRules:        tt.rules,
+					DefaultSecurityRules: []models.SecurityRule{allowVNet, denyAll},
+				},
+			}
+
+			rule, allowed := evaluateInbound(nsg, tt.port)
+			if rule != tt.wantRule || allowed != tt.wantAllowed {
+				t.Errorf("evaluateInbound() = (%q, %v), want (%q, %v)", rule, allowed, tt.wantRule, tt.wantAllowed)
+			}
+		})
+	}
+}
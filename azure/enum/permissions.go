@@ -0,0 +1,143 @@
+package management
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/f0rk3b0mb/GoCloudGhost/azure/auth"
+	"github.com/f0rk3b0mb/GoCloudGhost/azure/models"
+)
+
+// effectivePermissions is the merged set of permissions the token holds at a scope
+type effectivePermissions struct {
+	Scope       string
+	Permissions []models.Permission
+}
+
+// merged returns the deduplicated, sorted union of every list in the permission set
+func (e effectivePermissions) merged() models.Permission {
+	var merged models.Permission
+	for _, perm := range e.Permissions {
+		merged.Actions = append(merged.Actions, perm.Actions...)
+		merged.NotActions = append(merged.NotActions, perm.NotActions...)
+		merged.DataActions = append(merged.DataActions, perm.DataActions...)
+		merged.NotDataActions = append(merged.NotDataActions, perm.NotDataActions...)
+	}
+
+	merged.Actions = uniqueSorted(merged.Actions)
+	merged.NotActions = uniqueSorted(merged.NotActions)
+	merged.DataActions = uniqueSorted(merged.DataActions)
+	merged.NotDataActions = uniqueSorted(merged.NotDataActions)
+
+	return merged
+}
+
+func enumerateEffectivePermissions(token, subscriptionID string) error {
+	ctx := context.Background()
+
+	fmt.Println("\n=== WHOAMI ===")
+
+	if claims, err := auth.ParseTokenClaims(token); err == nil {
+		fmt.Printf("[INFO] Identity: %s  Object ID: %s  Tenant: %s\n",
+			claims.Identity(),
+			claims.ObjectID,
+			claims.TenantID,
+		)
+	} else {
+		fmt.Printf("[WARN] Could not decode token claims: %v\n", err)
+	}
+
+	subscriptionScope := "/subscriptions/" + subscriptionID
+	subscriptionPerms, err := getEffectivePermissions(ctx, token, subscriptionScope)
+	if err != nil {
+		return err
+	}
+	reportEffectivePermissions(subscriptionPerms)
+
+	groups, err := listResourceGroups(ctx, token, subscriptionID)
+	if err != nil {
+		fmt.Printf("[WARN] Could not list resource groups: %v\n", err)
+		return nil
+	}
+
+	subscriptionSet := subscriptionPerms.merged()
+
+	// Resource groups that inherit exactly the subscription permissions add nothing new
+	for _, group := range groups {
+		groupPerms, err := getEffectivePermissions(ctx, token, group.ID)
+		if err != nil {
+			fmt.Printf("[WARN] Permission request failed for %s: %v\n", group.Name, err)
+			continue
+		}
+
+		if samePermissions(subscriptionSet, groupPerms.merged()) {
+			continue
+		}
+		reportEffectivePermissions(groupPerms)
+	}
+
+	return nil
+}
+
+func getEffectivePermissions(ctx context.Context, token, scope string) (effectivePermissions, error) {
+	url := fmt.Sprintf(
+		"https://management.azure.com%s/providers/Microsoft.Authorization/permissions?api-version=2022-04-01",
+		scope,
+	)
+
+	var result models.PermissionsResponse
+	if err := makeAuthenticatedRequest(ctx, token, http.MethodGet, url, &result); err != nil {
+		return effectivePermissions{}, err
+	}
+
+	return effectivePermissions{Scope: scope, Permissions: result.Value}, nil
+}
+
+func reportEffectivePermissions(perms effectivePermissions) {
+	merged := perms.merged()
+
+	fmt.Printf("\n[INFO] Scope: %s\n", perms.Scope)
+	printPermissionList("Actions", merged.Actions)
+	printPermissionList("NotActions", merged.NotActions)
+	printPermissionList("DataActions", merged.DataActions)
+	printPermissionList("NotDataActions", merged.NotDataActions)
+
+	for _, match := range riskEngine.Evaluate(perms.Permissions) {
+		fmt.Println(models.Finding{
+			Severity: match.Rule.Severity,
+			Resource: perms.Scope,
+			Title:    match.Rule.Title,
+			Evidence: match.Operation + " granted by " + match.Grant,
+		})
+	}
+}
+
+func printPermissionList(label string, values []string) {
+	if len(values) == 0 {
+		return
+	}
+	fmt.Printf("  %-15s %s\n", label+":", strings.Join(values, ", "))
+}
+
+func samePermissions(a, b models.Permission) bool {
+	return strings.Join(a.Actions, ",") == strings.Join(b.Actions, ",") &&
+		strings.Join(a.NotActions, ",") == strings.Join(b.NotActions, ",") &&
+		strings.Join(a.DataActions, ",") == strings.Join(b.DataActions, ",") &&
+		strings.Join(a.NotDataActions, ",") == strings.Join(b.NotDataActions, ",")
+}
+
+func uniqueSorted(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	var unique []string
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	sort.Strings(unique)
+	return unique
+}
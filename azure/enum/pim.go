@@ -0,0 +1,139 @@
+package management
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/f0rk3b0mb/GoCloudGhost/azure/models"
+	"github.com/f0rk3b0mb/GoCloudGhost/azure/rbac"
+)
+
+const pimAPIVersion = "2020-10-01"
+
+// enumeratePIM lists Privileged Identity Management eligible and active role schedule
+// instances at management group, subscription, resource group and resource scope
+func enumeratePIM(token, subscriptionID, graphToken string) error {
+	ctx := context.Background()
+
+	roleMap, err := listRoleDefinitions(ctx, token, subscriptionID)
+	if err != nil {
+		return err
+	}
+
+	hierarchy := discoverManagementGroups(ctx, token, subscriptionID)
+	subscriptionScope := "/subscriptions/" + subscriptionID
+
+	eligible := listScheduleInstances(ctx, token, hierarchy.Groups, subscriptionScope, "roleEligibilityScheduleInstances")
+	active := listScheduleInstances(ctx, token, hierarchy.Groups, subscriptionScope, "roleAssignmentScheduleInstances")
+
+	var principalIDs []string
+	for _, instance := range eligible {
+		principalIDs = append(principalIDs, instance.Properties.PrincipalID)
+	}
+	for _, instance := range active {
+		principalIDs = append(principalIDs, instance.Properties.PrincipalID)
+	}
+	principals := resolvePrincipals(graphToken, principalIDs)
+
+	fmt.Println("\n=== PIM ELIGIBLE ASSIGNMENTS ===")
+	if len(eligible) == 0 {
+		fmt.Println("[INFO] No eligible assignments found.")
+	}
+	for _, instance := range eligible {
+		reportScheduleInstance(ctx, token, roleMap, principals, instance, true)
+	}
+
+	fmt.Println("\n=== PIM ACTIVE ASSIGNMENTS ===")
+	if len(active) == 0 {
+		fmt.Println("[INFO] No active schedule instances found.")
+	}
+	for _, instance := range active {
+		reportScheduleInstance(ctx, token, roleMap, principals, instance, false)
+	}
+
+	return nil
+}
+
+// listScheduleInstances collects the instances made directly at each management group, and
+// every instance at or below the subscription, including those on resource groups and
+// individual resources. Instances inherited from above are returned again by the
+// subscription listing and are deduplicated by ID.
+func listScheduleInstances(ctx context.Context, token string, groups []string, subscriptionScope, resource string) []models.RoleScheduleInstance {
+	var instances []models.RoleScheduleInstance
+	seen := make(map[string]bool)
+
+	list := func(scope, filter string) {
+		url := fmt.Sprintf(
+			"https://management.azure.com%s/providers/Microsoft.Authorization/%s?api-version=%s%s",
+			scope,
+			resource,
+			pimAPIVersion,
+			filter,
+		)
+
+		page, err := listAllPages[models.RoleScheduleInstance](ctx, token, url)
+		if err != nil {
+			fmt.Printf("[WARN] %s request failed for %s: %v\n", resource, scope, err)
+			return
+		}
+
+		for _, instance := range page {
+			if !seen[instance.ID] {
+				seen[instance.ID] = true
+				instances = append(instances, instance)
+			}
+		}
+	}
+
+	for _, group := range groups {
+		list(group, "&$filter=atScope()")
+	}
+	list(subscriptionScope, "")
+
+	return instances
+}
+
+func reportScheduleInstance(ctx context.Context, token string, roleMap map[string]models.RoleDefinition, principals map[string]models.Principal, instance models.RoleScheduleInstance, eligible bool) {
+	props := instance.Properties
+	principal := principalFor(principals, props.PrincipalID, props.PrincipalType)
+
+	roleName := props.RoleDefinitionID
+	level := "[INFO]"
+	canAssignRoles := false
+	if role, ok := lookupRoleDefinition(ctx, token, roleMap, props.RoleDefinitionID); ok {
+		roleName = role.Properties.RoleName
+		level, _ = roleRiskLevel(role)
+		_, canAssignRoles = rbac.Allows(role.Properties.Permissions, "Microsoft.Authorization/roleAssignments/write", false)
+	}
+
+	end := props.EndDateTime
+	if end == "" {
+		end = "permanent"
+	}
+
+	fmt.Printf("%s Principal: %-50s Type: %-16s Role: %-30s Scope: %s (%s)\n",
+		level,
+		principal.Label(),
+		principal.Type,
+		roleName,
+		props.Scope,
+		scopeLevel(props.Scope),
+	)
+	fmt.Printf("    Member Type: %-10s Status: %-12s Start: %s  End: %s\n", props.MemberType, props.Status, props.StartDateTime, end)
+	if props.AssignmentType != "" {
+		fmt.Printf("    Assignment Type: %s\n", props.AssignmentType)
+	}
+	if props.Condition != "" {
+		fmt.Printf("    Condition: %s\n", props.Condition)
+	}
+
+	// Activating a role that can write role assignments grants control of the scope
+	if eligible && canAssignRoles {
+		fmt.Println(models.Finding{
+			Severity: models.SeverityCritical,
+			Resource: props.Scope,
+			Title:    "Eligible " + roleName + " grant can be activated",
+			Evidence: fmt.Sprintf("principal=%s memberType=%s end=%s", principal.Label(), props.MemberType, end),
+		})
+	}
+}
@@ -0,0 +1,348 @@
+package management
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/f0rk3b0mb/GoCloudGhost/azure/models"
+)
+
+const (
+	policyAPIVersion          = "2021-06-01"
+	policyExemptionAPIVersion = "2022-07-01-preview"
+	policyInsightsAPIVersion  = "2019-10-01"
+)
+
+// nonCompliantResourceLimit caps the non-compliant resources listed per run
+const nonCompliantResourceLimit = 200
+
+// parameterReference matches a policy value that takes its value from a parameter
+var parameterReference = regexp.MustCompile(`^\[parameters\('([^']+)'\)\]$`)
+
+// guardrailEffects block or change deployments rather than only reporting on them
+var guardrailEffects = map[string]bool{
+	"deny":              true,
+	"denyaction":        true,
+	"deployifnotexists": true,
+	"modify":            true,
+	"append":            true,
+}
+
+// policyResolver looks up and caches the definitions behind policy assignments
+type policyResolver struct {
+	ctx         context.Context
+	token       string
+	definitions map[string]models.PolicyDefinition
+}
+
+// enumeratePolicyAssignments lists the policy assignments that apply to the subscription,
+// including those inherited from management groups, with the effects they enforce, their
+// exemptions and the PolicyInsights compliance summary
+func enumeratePolicyAssignments(token, subscriptionID string) error {
+	ctx := context.Background()
+	base := "https://management.azure.com/subscriptions/" + subscriptionID + "/providers"
+
+	assignments, err := listAllPages[models.PolicyAssignment](ctx, token,
+		fmt.Sprintf("%s/Microsoft.Authorization/policyAssignments?api-version=%s", base, policyAPIVersion))
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("\n=== POLICY ASSIGNMENTS ===")
+
+	if len(assignments) == 0 {
+		fmt.Println("[INFO] No policy assignments apply to the subscription.")
+	}
+
+	// Built-in and custom definitions visible from the subscription, fetched in one listing
+	// instead of one request per initiative member
+	resolver := &policyResolver{ctx: ctx, token: token, definitions: make(map[string]models.PolicyDefinition)}
+	definitions, err := listAllPages[models.PolicyDefinition](ctx, token,
+		fmt.Sprintf("%s/Microsoft.Authorization/policyDefinitions?api-version=%s", base, policyAPIVersion))
+	if err != nil {
+		fmt.Printf("[WARN] Policy definition request failed, effects will be resolved one by one: %v\n", err)
+	}
+	for _, definition := range definitions {
+		resolver.definitions[strings.ToLower(definition.ID)] = definition
+	}
+
+	sort.SliceStable(assignments, func(i, j int) bool {
+		return len(assignments[i].Properties.Scope) < len(assignments[j].Properties.Scope)
+	})
+
+	guardrails := 0
+	for _, assignment := range assignments {
+		if printPolicyAssignment(resolver, assignment) {
+			guardrails++
+		}
+	}
+	if len(assignments) > 0 {
+		fmt.Printf("\n[INFO] %d of %d assignments enforce deny, modify or deployIfNotExists effects\n", guardrails, len(assignments))
+	}
+
+	enumeratePolicyExemptions(ctx, token, base)
+	summarizePolicyCompliance(ctx, token, base)
+
+	return nil
+}
+
+// printPolicyAssignment prints an assignment with its effects and reports whether it
+// enforces a guardrail effect
+func printPolicyAssignment(resolver *policyResolver, assignment models.PolicyAssignment) bool {
+	props := assignment.Properties
+	enforcement := valueOr(props.EnforcementMode, "Default")
+
+	effects, kind := resolver.effects(props)
+
+	fmt.Printf("\n[INFO] Assignment: %-45s Scope: %s\n", valueOr(props.DisplayName, assignment.Name), props.Scope)
+	fmt.Printf("       %s: %s  Enforcement: %s\n", kind, extractNameFromID(props.PolicyDefinitionID), enforcement)
+	fmt.Printf("       Effects: %s\n", describeEffects(effects))
+	for _, notScope := range props.NotScopes {
+		fmt.Printf("       Excluded scope: %s\n", notScope)
+	}
+
+	guardrail := false
+	for effect := range effects {
+		if guardrailEffects[effect] {
+			guardrail = true
+		}
+	}
+
+	if !guardrail {
+		return false
+	}
+	if strings.EqualFold(enforcement, "DoNotEnforce") {
+		fmt.Println(models.Finding{
+			Severity: models.SeverityLow,
+			Resource: valueOr(props.DisplayName, assignment.Name),
+			Title:    "Guardrail policy assigned but not enforced",
+			Evidence: "enforcementMode=DoNotEnforce, effects " + describeEffects(effects),
+		})
+		return false
+	}
+	return true
+}
+
+// effects resolves the effect of every policy behind an assignment and counts them. The
+// second return value tells a single policy from an initiative.
+func (r *policyResolver) effects(props models.PolicyAssignmentProperties) (map[string]int, string) {
+	effects := make(map[string]int)
+
+	if !strings.Contains(strings.ToLower(props.PolicyDefinitionID), "/policysetdefinitions/") {
+		definition, ok := r.definition(props.PolicyDefinitionID)
+		if ok {
+			effects[definitionEffect(definition, props.Parameters)]++
+		}
+		return effects, "Policy"
+	}
+
+	var set models.PolicySetDefinition
+	url := fmt.Sprintf("https://management.azure.com%s?api-version=%s", props.PolicyDefinitionID, policyAPIVersion)
+	if err := makeAuthenticatedRequest(r.ctx, r.token, http.MethodGet, url, &set); err != nil {
+		fmt.Printf("[WARN] Initiative request failed for %s: %v\n", extractNameFromID(props.PolicyDefinitionID), err)
+		return effects, "Initiative"
+	}
+
+	for _, member := range set.Properties.PolicyDefinitions {
+		definition, ok := r.definition(member.PolicyDefinitionID)
+		if !ok {
+			effects["unknown"]++
+			continue
+		}
+
+		// Member parameters may in turn reference the initiative's parameters
+		values := make(map[string]models.PolicyParameterValue, len(member.Parameters))
+		for name, value := range member.Parameters {
+			values[name] = models.PolicyParameterValue{Value: resolvePolicyValue(value.Value, props.Parameters, set.Properties.Parameters)}
+		}
+
+		effects[definitionEffect(definition, values)]++
+	}
+
+	return effects, "Initiative"
+}
+
+func (r *policyResolver) definition(id string) (models.PolicyDefinition, bool) {
+	key := strings.ToLower(id)
+	if definition, ok := r.definitions[key]; ok {
+		return definition, true
+	}
+
+	var definition models.PolicyDefinition
+	url := fmt.Sprintf("https://management.azure.com%s?api-version=%s", id, policyAPIVersion)
+	if err := makeAuthenticatedRequest(r.ctx, r.token, http.MethodGet, url, &definition); err != nil {
+		return definition, false
+	}
+
+	r.definitions[key] = definition
+	return definition, true
+}
+
+// definitionEffect resolves the effect of a policy definition given its parameter values
+func definitionEffect(definition models.PolicyDefinition, values map[string]models.PolicyParameterValue) string {
+	effect := resolvePolicyValue(definition.Properties.PolicyRule.Then.Effect, values, definition.Properties.Parameters)
+	if s, ok := effect.(string); ok && s != "" {
+		return strings.ToLower(s)
+	}
+	return "unknown"
+}
+
+// resolvePolicyValue replaces a [parameters('name')] reference with the supplied value or
+// the parameter's default
+func resolvePolicyValue(value interface{}, supplied map[string]models.PolicyParameterValue, defined map[string]models.PolicyParameterDefinition) interface{} {
+	s, ok := value.(string)
+	if !ok {
+		return value
+	}
+
+	match := parameterReference.FindStringSubmatch(s)
+	if match == nil {
+		return value
+	}
+
+	if v, ok := supplied[match[1]]; ok {
+		return v.Value
+	}
+	if d, ok := defined[match[1]]; ok {
+		return d.DefaultValue
+	}
+	return value
+}
+
+// describeEffects renders effect counts with the most common effect first
+func describeEffects(effects map[string]int) string {
+	if len(effects) == 0 {
+		return "<unresolved>"
+	}
+
+	names := make([]string, 0, len(effects))
+	for effect := range effects {
+		names = append(names, effect)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if effects[names[i]] != effects[names[j]] {
+			return effects[names[i]] > effects[names[j]]
+		}
+		return names[i] < names[j]
+	})
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s x%d", name, effects[name]))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func enumeratePolicyExemptions(ctx context.Context, token, base string) {
+	url := fmt.Sprintf("%s/Microsoft.Authorization/policyExemptions?api-version=%s", base, policyExemptionAPIVersion)
+
+	exemptions, err := listAllPages[models.PolicyExemption](ctx, token, url)
+	if err != nil {
+		fmt.Printf("[WARN] Policy exemption request failed: %v\n", err)
+		return
+	}
+
+	fmt.Println("\n=== POLICY EXEMPTIONS ===")
+
+	if len(exemptions) == 0 {
+		fmt.Println("[INFO] No policy exemptions found.")
+		return
+	}
+
+	for _, exemption := range exemptions {
+		props := exemption.Properties
+		scope := exemption.ID
+		if i := strings.Index(strings.ToLower(scope), "/providers/microsoft.authorization/policyexemptions/"); i >= 0 {
+			scope = scope[:i]
+		}
+
+		fmt.Printf("[INFO] Exemption: %-40s Category: %-10s Assignment: %-40s Expires: %-25s Scope: %s\n",
+			valueOr(props.DisplayName, exemption.Name),
+			props.ExemptionCategory,
+			extractNameFromID(props.PolicyAssignmentID),
+			valueOr(props.ExpiresOn, "never"),
+			valueOr(scope, "/"),
+		)
+
+		if props.ExpiresOn == "" && strings.EqualFold(props.ExemptionCategory, "Waiver") {
+			fmt.Println(models.Finding{
+				Severity: models.SeverityLow,
+				Resource: valueOr(props.DisplayName, exemption.Name),
+				Title:    "Permanent policy waiver",
+				Evidence: "assignment " + extractNameFromID(props.PolicyAssignmentID) + " waived at " + valueOr(scope, "/"),
+			})
+		}
+	}
+}
+
+// summarizePolicyCompliance prints the PolicyInsights compliance summary per assignment and
+// the resources currently non-compliant
+func summarizePolicyCompliance(ctx context.Context, token, base string) {
+	url := fmt.Sprintf("%s/Microsoft.PolicyInsights/policyStates/latest/summarize?api-version=%s", base, policyInsightsAPIVersion)
+
+	var summary models.PolicyStatesSummary
+	if err := makeAuthenticatedRequest(ctx, token, http.MethodPost, url, &summary); err != nil {
+		fmt.Printf("[WARN] Policy compliance request failed: %v\n", err)
+		return
+	}
+
+	fmt.Println("\n=== POLICY COMPLIANCE ===")
+
+	if len(summary.Value) == 0 {
+		fmt.Println("[INFO] No compliance data available.")
+		return
+	}
+
+	result := summary.Value[0]
+	fmt.Printf("[INFO] Non-compliant resources: %d  Non-compliant policies: %d\n",
+		result.Results.NonCompliantResources,
+		result.Results.NonCompliantPolicies,
+	)
+
+	for _, assignment := range result.PolicyAssignments {
+		if assignment.Results.NonCompliantResources == 0 {
+			continue
+		}
+		fmt.Printf("[INFO] Assignment: %-45s Non-compliant resources: %d\n",
+			extractNameFromID(assignment.PolicyAssignmentID),
+			assignment.Results.NonCompliantResources,
+		)
+	}
+
+	if result.Results.NonCompliantResources == 0 {
+		return
+	}
+
+	statesURL := fmt.Sprintf(
+		"%s/Microsoft.PolicyInsights/policyStates/latest/queryResults?api-version=%s&$top=%d&$filter=complianceState%%20eq%%20'NonCompliant'",
+		base,
+		policyInsightsAPIVersion,
+		nonCompliantResourceLimit,
+	)
+
+	var states struct {
+		Value    []models.PolicyState `json:"value"`
+		NextLink string               `json:"@odata.nextLink"`
+	}
+	if err := makeAuthenticatedRequest(ctx, token, http.MethodPost, statesURL, &states); err != nil {
+		fmt.Printf("[WARN] Non-compliant resource request failed: %v\n", err)
+		return
+	}
+
+	fmt.Println("\n--- Non-compliant resources ---")
+	for _, state := range states.Value {
+		fmt.Printf("[INFO] %-80s Assignment: %-30s Policy: %-40s Effect: %s\n",
+			state.ResourceID,
+			state.PolicyAssignmentName,
+			state.PolicyDefinitionName,
+			state.PolicyDefinitionAction,
+		)
+	}
+	if states.NextLink != "" {
+		fmt.Printf("[INFO] Showing the first %d non-compliant states\n", nonCompliantResourceLimit)
+	}
+}
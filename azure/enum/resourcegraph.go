@@ -0,0 +1,290 @@
+package management
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/f0rk3b0mb/GoCloudGhost/azure/models"
+	"github.com/spf13/cobra"
+)
+
+const (
+	resourceGraphURL        = "https://management.azure.com/providers/Microsoft.ResourceGraph/resources?api-version=2022-10-01"
+	subscriptionsURL        = "https://management.azure.com/subscriptions?api-version=2020-01-01"
+	resourceGraphPageSize   = 1000
+	resourceGraphMaxSubs    = 1000
+	resourceGraphCellLength = 80
+)
+
+// inventoryQuery lists every resource the token can read, with the columns needed to
+// record it in the session
+const inventoryQuery = `Resources
+| project id, name, type, location, resourceGroup, subscriptionId, kind, sku = tostring(sku.name), identity = tostring(identity.type)
+| order by type asc, name asc`
+
+// inventorySummaryQuery counts resources per type
+const inventorySummaryQuery = `Resources
+| summarize resources = count() by type
+| order by resources desc`
+
+var ResourceGraphCmd = &cobra.Command{
+	Use:   "resourcegraph",
+	Short: "Run Azure Resource Graph queries across all subscriptions",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cliToken, _ := cmd.Flags().GetString("token")
+		query, _ := cmd.Flags().GetString("query")
+		inventory, _ := cmd.Flags().GetBool("inventory")
+		subscriptions, _ := cmd.Flags().GetStringSlice("subscription")
+		format, _ := cmd.Flags().GetString("format")
+
+		if query == "" && !inventory {
+			return fmt.Errorf("either --query or --inventory is required")
+		}
+		if format != "table" && format != "json" {
+			return fmt.Errorf("unsupported format %q, use table or json", format)
+		}
+
+		token, err := loadTokenFromMultipleSources(cliToken)
+		if err != nil {
+			return err
+		}
+
+		ctx := context.Background()
+
+		if len(subscriptions) == 0 {
+			subscriptions, err = listSubscriptionIDs(ctx, token)
+			if err != nil {
+				return fmt.Errorf("failed to list subscriptions: %w", err)
+			}
+			if len(subscriptions) == 0 {
+				return fmt.Errorf("the token has no access to any subscription")
+			}
+		}
+
+		if inventory {
+			return runInventory(ctx, token, subscriptions, format)
+		}
+
+		result, err := queryResourceGraph(ctx, token, subscriptions, query)
+		if err != nil {
+			return err
+		}
+		return printResourceGraphResult(result, format)
+	},
+}
+
+func init() {
+	ResourceGraphCmd.Flags().String("token", "", "Azure access token")
+	ResourceGraphCmd.Flags().String("query", "", "KQL query to run against the Resources tables")
+	ResourceGraphCmd.Flags().Bool("inventory", false, "Inventory every resource in every accessible subscription")
+	ResourceGraphCmd.Flags().StringSlice("subscription", nil, "Limit the query to these subscription IDs (default: all accessible subscriptions)")
+	ResourceGraphCmd.Flags().String("format", "table", "Output format: table or json")
+}
+
+// resourceGraphResult holds all pages of a query in the table result format
+type resourceGraphResult struct {
+	Columns []models.ResourceGraphColumn
+	Rows    [][]interface{}
+}
+
+// Objects returns the rows keyed by column name
+func (r resourceGraphResult) Objects() []map[string]interface{} {
+	objects := make([]map[string]interface{}, 0, len(r.Rows))
+	for _, row := range r.Rows {
+		object := make(map[string]interface{}, len(r.Columns))
+		for i, column := range r.Columns {
+			if i < len(row) {
+				object[column.Name] = row[i]
+			}
+		}
+		objects = append(objects, object)
+	}
+	return objects
+}
+
+// listSubscriptionIDs returns every subscription the token can see
+func listSubscriptionIDs(ctx context.Context, token string) ([]string, error) {
+	subscriptions, err := listAllPages[models.Subscription](ctx, token, subscriptionsURL)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		ids = append(ids, subscription.SubscriptionID)
+	}
+	return ids, nil
+}
+
+// queryResourceGraph runs query over the subscriptions, batching them to the API limit
+// and following $skipToken until every page has been read
+func queryResourceGraph(ctx context.Context, token string, subscriptions []string, query string) (resourceGraphResult, error) {
+	var result resourceGraphResult
+
+	for start := 0; start < len(subscriptions); start += resourceGraphMaxSubs {
+		end := start + resourceGraphMaxSubs
+		if end > len(subscriptions) {
+			end = len(subscriptions)
+		}
+
+		request := models.ResourceGraphRequest{
+			Subscriptions: subscriptions[start:end],
+			Query:         query,
+			Options: models.ResourceGraphOptions{
+				ResultFormat: "table",
+				Top:          resourceGraphPageSize,
+			},
+		}
+
+		for {
+			var page models.ResourceGraphResponse
+			if err := postAuthenticatedJSON(ctx, token, resourceGraphURL, request, &page); err != nil {
+				return result, fmt.Errorf("resource graph query failed: %w", err)
+			}
+
+			if result.Columns == nil {
+				result.Columns = page.Data.Columns
+			}
+			result.Rows = append(result.Rows, page.Data.Rows...)
+
+			if page.SkipToken == "" {
+				break
+			}
+			request.Options.SkipToken = page.SkipToken
+		}
+	}
+
+	return result, nil
+}
+
+// runInventory prints a per-type summary, saves the full inventory to loot and records
+// the resources in the session for attack path analysis and exports
+func runInventory(ctx context.Context, token string, subscriptions []string, format string) error {
+	inventory, err := queryResourceGraph(ctx, token, subscriptions, inventoryQuery)
+	if err != nil {
+		return err
+	}
+
+	objects := inventory.Objects()
+
+	resources := make([]models.Resource, 0, len(objects))
+	for _, object := range objects {
+		resources = append(resources, models.Resource{
+			ID:       stringValue(object["id"]),
+			Name:     stringValue(object["name"]),
+			Type:     stringValue(object["type"]),
+			Location: stringValue(object["location"]),
+		})
+	}
+	recordResources(resources...)
+
+	data, err := json.MarshalIndent(objects, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// JSON output goes to stdout untouched so it can be piped into other tools, status
+	// messages go to stderr
+	status := os.Stdout
+	if format == "json" {
+		fmt.Println(string(data))
+		status = os.Stderr
+	} else {
+		summary, err := queryResourceGraph(ctx, token, subscriptions, inventorySummaryQuery)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println("\n=== RESOURCE INVENTORY ===")
+		fmt.Printf("[INFO] %d resources across %d subscriptions\n\n", len(inventory.Rows), len(subscriptions))
+		if err := printResourceGraphTable(summary); err != nil {
+			return err
+		}
+		fmt.Println()
+	}
+
+	path, err := saveLoot(data, "resourcegraph", "inventory.json")
+	if err != nil {
+		fmt.Fprintf(status, "[WARN] Failed to save inventory: %v\n", err)
+		return nil
+	}
+	fmt.Fprintf(status, "[INFO] Full inventory saved to %s\n", path)
+
+	return nil
+}
+
+func printResourceGraphResult(result resourceGraphResult, format string) error {
+	if format == "json" {
+		return printJSON(result.Objects())
+	}
+
+	if err := printResourceGraphTable(result); err != nil {
+		return err
+	}
+	fmt.Printf("\n[INFO] %d rows\n", len(result.Rows))
+	return nil
+}
+
+func printJSON(v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+// printResourceGraphTable prints the rows aligned under their column names, with long
+// cells truncated. Use --format json for the complete values.
+func printResourceGraphTable(result resourceGraphResult) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	headers := make([]string, len(result.Columns))
+	for i, column := range result.Columns {
+		headers[i] = column.Name
+	}
+	fmt.Fprintln(w, strings.Join(headers, "\t"))
+
+	for _, row := range result.Rows {
+		cells := make([]string, len(result.Columns))
+		for i := range result.Columns {
+			if i < len(row) {
+				cells[i] = truncateCell(cellString(row[i]))
+			}
+		}
+		fmt.Fprintln(w, strings.Join(cells, "\t"))
+	}
+
+	return w.Flush()
+}
+
+func cellString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case map[string]interface{}, []interface{}:
+		data, _ := json.Marshal(v)
+		return string(data)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func truncateCell(value string) string {
+	value = strings.Join(strings.Fields(value), " ")
+	if len(value) > resourceGraphCellLength {
+		return value[:resourceGraphCellLength-3] + "..."
+	}
+	return value
+}
+
+func stringValue(value interface{}) string {
+	s, _ := value.(string)
+	return s
+}
@@ -0,0 +1,156 @@
+package management
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/f0rk3b0mb/GoCloudGhost/azure/models"
+)
+
+// secretPatterns match credential material regardless of the field it is stored in
+var secretPatterns = []struct {
+	Name     string
+	Severity string
+	Pattern  *regexp.Regexp
+}{
+	{"Storage account key in connection string", models.SeverityCritical, regexp.MustCompile(`AccountKey=[A-Za-z0-9+/=]{40,}`)},
+	{"Shared access signature", models.SeverityHigh, regexp.MustCompile(`[?&]sig=[A-Za-z0-9%+/=]{20,}`)},
+	{"Service Bus / Event Hub shared access key", models.SeverityHigh, regexp.MustCompile(`SharedAccessKey=[A-Za-z0-9+/=]{20,}`)},
+	{"Private key", models.SeverityCritical, regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`)},
+	{"JSON web token", models.SeverityHigh, regexp.MustCompile(`eyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]+`)},
+	{"Entra ID client secret", models.SeverityCritical, regexp.MustCompile(`[A-Za-z0-9_~.-]{3}8Q~[A-Za-z0-9_~.-]{31,34}`)},
+	{"AWS access key ID", models.SeverityHigh, regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"GitHub token", models.SeverityHigh, regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36}\b`)},
+	{"Password in connection string", models.SeverityHigh, regexp.MustCompile(`(?i)\b(password|pwd)=[^;'"\s]{3,}`)},
+}
+
+var (
+	sensitiveKeyPattern = regexp.MustCompile(`(?i)(password|passwd|pwd|secret|token|apikey|api_key|accesskey|access_key|connectionstring|credential|privatekey|sastoken)`)
+
+	// Keys that name or point at a secret rather than hold it, e.g. secretName in a Key Vault reference
+	referenceKeyPattern = regexp.MustCompile(`(?i)(name|uri|url|id|type|version|expiry|expiration)$`)
+)
+
+// scanForSecrets walks decoded JSON and returns a finding for every value that looks like credential material
+func scanForSecrets(resource string, data interface{}) []models.Finding {
+	var findings []models.Finding
+
+	walkJSON("", data, func(path, value string) {
+		if finding, ok := matchSecret(resource, path, value); ok {
+			findings = append(findings, finding)
+		}
+	})
+
+	return findings
+}
+
+// saveAndScan writes record to loot and prints a finding for every secret found in it
+func saveAndScan(resource string, record interface{}, parts ...string) {
+	data, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		fmt.Printf("[WARN] Could not encode %s: %v\n", resource, err)
+		return
+	}
+
+	if path, err := saveLoot(data, parts...); err != nil {
+		fmt.Printf("[WARN] Could not save %s: %v\n", resource, err)
+	} else {
+		fmt.Printf("[INFO] Saved to %s\n", path)
+	}
+
+	// Round trip through JSON so the scanner sees plain maps for every section
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return
+	}
+
+	for _, finding := range scanForSecrets(resource, decoded) {
+		fmt.Println(finding)
+	}
+}
+
+func matchSecret(resource, path, value string) (models.Finding, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return models.Finding{}, false
+	}
+
+	for _, pattern := range secretPatterns {
+		if match := pattern.Pattern.FindString(value); match != "" {
+			return models.Finding{
+				Severity: pattern.Severity,
+				Resource: resource,
+				Title:    pattern.Name,
+				Evidence: fmt.Sprintf("%s=%s", path, match),
+			}, true
+		}
+	}
+
+	// ARM template expressions such as [parameters('adminPassword')] are references, not values
+	if strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") {
+		return models.Finding{}, false
+	}
+
+	// So are Logic App workflow expressions such as @parameters('sqlPassword') or
+	// @{body('Get_secret')?['value']}. "@@" escapes a literal @ and is a plain value.
+	if strings.HasPrefix(value, "@") && !strings.HasPrefix(value, "@@") {
+		return models.Finding{}, false
+	}
+
+	key := meaningfulKey(path)
+	if sensitiveKeyPattern.MatchString(key) && !referenceKeyPattern.MatchString(key) {
+		return models.Finding{
+			Severity: models.SeverityHigh,
+			Resource: resource,
+			Title:    "Possible secret in sensitive field",
+			Evidence: fmt.Sprintf("%s=%s", path, value),
+		}, true
+	}
+
+	return models.Finding{}, false
+}
+
+// meaningfulKey returns the last path segment that names the data, skipping generic
+// wrappers such as the "value" objects ARM uses for parameters and outputs
+func meaningfulKey(path string) string {
+	segments := strings.Split(path, ".")
+	for i := len(segments) - 1; i >= 0; i-- {
+		segment := segments[i]
+		switch {
+		case segment == "value", segment == "defaultValue", strings.HasPrefix(segment, "["):
+			continue
+		default:
+			return segment
+		}
+	}
+	return ""
+}
+
+// walkJSON calls fn for every string leaf in decoded JSON, visiting object keys in sorted order
+func walkJSON(path string, data interface{}, fn func(path, value string)) {
+	switch v := data.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			walkJSON(childPath, v[key], fn)
+		}
+	case []interface{}:
+		for i, item := range v {
+			walkJSON(fmt.Sprintf("%s.[%d]", path, i), item, fn)
+		}
+	case string:
+		fn(path, v)
+	}
+}
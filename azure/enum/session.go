@@ -0,0 +1,53 @@
+package management
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/f0rk3b0mb/GoCloudGhost/azure/models"
+	"github.com/f0rk3b0mb/GoCloudGhost/session"
+)
+
+// Session entries holding the ARM data collected by the management command. Entries are
+// merged across runs so several subscriptions can be enumerated into one session.
+const (
+	RoleAssignmentsSession  = "azure_role_assignments"
+	RoleDefinitionsSession  = "azure_role_definitions"
+	ManagementGroupsSession = "azure_management_groups"
+	ResourcesSession        = "azure_resources"
+	IdentitiesSession       = "azure_identities"
+)
+
+// mergeSession adds items to the list stored under name, replacing entries with the same key
+func mergeSession[T any](name string, items []T, key func(T) string) {
+	var stored []T
+	if _, err := session.Load(name, &stored); err != nil {
+		fmt.Printf("[WARN] %v\n", err)
+		return
+	}
+
+	index := make(map[string]int, len(stored))
+	for i, item := range stored {
+		index[key(item)] = i
+	}
+
+	for _, item := range items {
+		if i, ok := index[key(item)]; ok {
+			stored[i] = item
+			continue
+		}
+		index[key(item)] = len(stored)
+		stored = append(stored, item)
+	}
+
+	if err := session.Save(name, stored); err != nil {
+		fmt.Printf("[WARN] %v\n", err)
+	}
+}
+
+// recordResources adds resources to the session for attack path analysis and exports
+func recordResources(resources ...models.Resource) {
+	mergeSession(ResourcesSession, resources, func(r models.Resource) string {
+		return strings.ToLower(r.ID)
+	})
+}
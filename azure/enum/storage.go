@@ -0,0 +1,101 @@
+package management
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/f0rk3b0mb/GoCloudGhost/azure/blob"
+	"github.com/f0rk3b0mb/GoCloudGhost/azure/models"
+)
+
+// auditStorageAccount checks the security relevant settings of a storage account
+// and returns a finding for every setting that is weaker than recommended
+func auditStorageAccount(account models.StorageAccount) []models.Finding {
+	props := account.Properties
+	var findings []models.Finding
+
+	add := func(severity, title, evidence string) {
+		findings = append(findings, models.Finding{
+			Severity: severity,
+			Resource: account.Name,
+			Title:    title,
+			Evidence: evidence,
+		})
+	}
+
+	// A missing value means the account predates the setting and public access is allowed
+	if props.AllowBlobPublicAccess == nil || *props.AllowBlobPublicAccess {
+		add(models.SeverityHigh, "Anonymous blob access allowed", "allowBlobPublicAccess="+boolSetting(props.AllowBlobPublicAccess, true))
+	}
+
+	if props.AllowSharedKeyAccess == nil || *props.AllowSharedKeyAccess {
+		add(models.SeverityMedium, "Shared key authorization enabled", "allowSharedKeyAccess="+boolSetting(props.AllowSharedKeyAccess, true))
+	}
+
+	switch props.MinimumTLSVersion {
+	case "TLS1_2", "TLS1_3":
+	case "":
+		add(models.SeverityMedium, "Legacy TLS versions accepted", "minimumTlsVersion=<unset, defaults to TLS1_0>")
+	default:
+		add(models.SeverityMedium, "Legacy TLS versions accepted", "minimumTlsVersion="+props.MinimumTLSVersion)
+	}
+
+	if props.SupportsHTTPSTrafficOnly != nil && !*props.SupportsHTTPSTrafficOnly {
+		add(models.SeverityHigh, "Unencrypted HTTP traffic allowed", "supportsHttpsTrafficOnly=false")
+	}
+
+	if props.NetworkACLs == nil || props.NetworkACLs.DefaultAction != "Deny" {
+		action := "<unset, defaults to Allow>"
+		if props.NetworkACLs != nil && props.NetworkACLs.DefaultAction != "" {
+			action = props.NetworkACLs.DefaultAction
+		}
+		add(models.SeverityMedium, "Reachable from all networks", "networkAcls.defaultAction="+action)
+	}
+
+	if props.SASPolicy == nil || props.SASPolicy.SASExpirationPeriod == "" {
+		add(models.SeverityLow, "No SAS expiration policy", "sasPolicy=<unset>")
+	}
+
+	if props.Encryption == nil || props.Encryption.RequireInfrastructureEncryption == nil || !*props.Encryption.RequireInfrastructureEncryption {
+		var setting *bool
+		if props.Encryption != nil {
+			setting = props.Encryption.RequireInfrastructureEncryption
+		}
+		add(models.SeverityLow, "Infrastructure encryption disabled", "encryption.requireInfrastructureEncryption="+boolSetting(setting, false))
+	}
+
+	return findings
+}
+
+// boolSetting renders an optional boolean setting, noting when the platform default applies
+func boolSetting(value *bool, platformDefault bool) string {
+	if value == nil {
+		return fmt.Sprintf("<unset, defaults to %t>", platformDefault)
+	}
+	return fmt.Sprintf("%t", *value)
+}
+
+// lootStorageAccount uses harvested shared keys to enumerate the account's data plane,
+// falling back to the next key only when one is rejected before anything was listed
+func lootStorageAccount(account string, keys []models.StorageAccountKey) {
+	fmt.Printf("\n--- Containers in %s ---\n", account)
+
+	for _, key := range keys {
+		if key.Value == "" {
+			continue
+		}
+
+		err := blob.EnumerateAccount(account, key.Value)
+		if err == nil {
+			return
+		}
+		fmt.Printf("[WARN] Data plane enumeration with %s failed for %s: %v\n", key.KeyName, account, err)
+
+		// Containers may already have been printed, so listing again would duplicate them
+		if !errors.Is(err, blob.ErrKeyRejected) {
+			return
+		}
+	}
+
+	fmt.Printf("[WARN] No usable key to enumerate containers in %s\n", account)
+}
@@ -0,0 +1,94 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/joho/godotenv"
+)
+
+const graphBaseURL = "https://graph.microsoft.com/v1.0"
+
+// LoadToken returns a Microsoft Graph token from the CLI flag, the GRAPH_ACCESS_TOKEN
+// environment variable or the .env file, in that order
+func LoadToken(cliToken string) (string, error) {
+	if cliToken != "" {
+		return cliToken, nil
+	}
+
+	if envToken := os.Getenv("GRAPH_ACCESS_TOKEN"); envToken != "" {
+		return envToken, nil
+	}
+
+	_ = godotenv.Load() // Ignore error if .env doesn't exist
+	if envToken := os.Getenv("GRAPH_ACCESS_TOKEN"); envToken != "" {
+		return envToken, nil
+	}
+
+	return "", fmt.Errorf("Microsoft Graph token is required. Provide it via:\n  1. --graph-token flag\n  2. GRAPH_ACCESS_TOKEN environment variable\n  3. GRAPH_ACCESS_TOKEN in .env file (set by 'azure auth')")
+}
+
+// makeGraphRequest performs an authenticated Graph request, sending body as JSON when
+// it is not nil, and decodes the JSON response into result
+func makeGraphRequest(ctx context.Context, token, method, url string, body, result interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(respBody))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return nil
+}
+
+// listAllGraphPages collects the value array of a Graph collection, following @odata.nextLink until exhausted
+func listAllGraphPages[T any](ctx context.Context, token, url string) ([]T, error) {
+	var items []T
+
+	for url != "" {
+		var page struct {
+			Value    []T    `json:"value"`
+			NextLink string `json:"@odata.nextLink"`
+		}
+		if err := makeGraphRequest(ctx, token, http.MethodGet, url, nil, &page); err != nil {
+			return nil, err
+		}
+
+		items = append(items, page.Value...)
+		url = page.NextLink
+	}
+
+	return items, nil
+}
@@ -0,0 +1,393 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/f0rk3b0mb/GoCloudGhost/azure/models"
+	"github.com/f0rk3b0mb/GoCloudGhost/session"
+	"github.com/spf13/cobra"
+)
+
+// ConditionalAccessSession is the session entry holding policies and named locations
+const ConditionalAccessSession = "graph_conditional_access"
+
+const (
+	policyStateEnabled    = "enabled"
+	policyStateReportOnly = "enabledForReportingButNotEnforced"
+)
+
+// legacyClientAppTypes are the client app types that cannot perform modern authentication
+var legacyClientAppTypes = []string{"exchangeActiveSync", "other"}
+
+// Trusted ranges with at least this many host bits are reported, /16 for IPv4 and /32 for IPv6
+const (
+	broadIPv4HostBits = 16
+	broadIPv6HostBits = 96
+)
+
+var conditionalAccessCmd = &cobra.Command{
+	Use:   "conditional-access",
+	Short: "Audit Conditional Access policies and named locations for MFA and legacy authentication gaps",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		token, err := tokenFromCmd(cmd)
+		if err != nil {
+			return err
+		}
+		return auditConditionalAccess(token)
+	},
+}
+
+func auditConditionalAccess(token string) error {
+	ctx := context.Background()
+
+	policies, err := listAllGraphPages[models.ConditionalAccessPolicy](ctx, token, graphBaseURL+"/identity/conditionalAccess/policies")
+	if err != nil {
+		return err
+	}
+
+	locations, err := listAllGraphPages[models.NamedLocation](ctx, token, graphBaseURL+"/identity/conditionalAccess/namedLocations")
+	if err != nil {
+		fmt.Printf("[WARN] Named location request failed: %v\n", err)
+	}
+
+	sort.Slice(policies, func(i, j int) bool {
+		return policies[i].DisplayName < policies[j].DisplayName
+	})
+
+	locationNames := map[string]string{"All": "Any location", "AllTrusted": "All trusted locations"}
+	for _, location := range locations {
+		locationNames[location.ID] = location.DisplayName
+	}
+
+	reportNamedLocations(locations)
+
+	names := newExclusionNamer(ctx, token, policies, locationNames)
+
+	fmt.Println("\n=== CONDITIONAL ACCESS POLICIES ===")
+
+	for _, policy := range policies {
+		reportPolicy(policy, names)
+	}
+
+	fmt.Println("\n=== CONDITIONAL ACCESS GAPS ===")
+
+	reportTenantGaps(policies)
+
+	return session.Save(ConditionalAccessSession, models.ConditionalAccessSnapshot{
+		Policies:       policies,
+		NamedLocations: locations,
+	})
+}
+
+func reportNamedLocations(locations []models.NamedLocation) {
+	fmt.Println("\n=== NAMED LOCATIONS ===")
+
+	if len(locations) == 0 {
+		fmt.Println("[INFO] No named locations defined.")
+		return
+	}
+
+	for _, location := range locations {
+		if len(location.CountriesAndRegions) > 0 {
+			fmt.Printf("[INFO] Country Location: %-35s Countries: %s  Include Unknown: %t\n",
+				location.DisplayName,
+				strings.Join(location.CountriesAndRegions, ","),
+				location.IncludeUnknownCountriesAndRegions,
+			)
+			continue
+		}
+
+		ranges := make([]string, 0, len(location.IPRanges))
+		for _, ipRange := range location.IPRanges {
+			ranges = append(ranges, ipRange.CIDRAddress)
+		}
+		fmt.Printf("[INFO] IP Location: %-40s Trusted: %-5t Ranges: %s\n", location.DisplayName, location.IsTrusted, strings.Join(ranges, ","))
+
+		// Untrusted locations only matter when a policy references them, trusted ones
+		// satisfy every policy that excludes AllTrusted and skip MFA registration prompts
+		if !location.IsTrusted {
+			continue
+		}
+
+		for _, ipRange := range location.IPRanges {
+			severity, broad := broadRangeSeverity(ipRange.CIDRAddress)
+			if !broad {
+				continue
+			}
+			fmt.Println(models.Finding{
+				Severity: severity,
+				Resource: location.DisplayName,
+				Title:    "Trusted named location covers a broad IP range",
+				Evidence: ipRange.CIDRAddress,
+			})
+		}
+	}
+}
+
+// broadRangeSeverity reports whether a CIDR is too broad to be a trusted corporate egress
+func broadRangeSeverity(cidr string) (string, bool) {
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return "", false
+	}
+
+	ones, bits := network.Mask.Size()
+	hostBits := bits - ones
+
+	threshold := broadIPv4HostBits
+	if bits == 128 {
+		threshold = broadIPv6HostBits
+	}
+
+	switch {
+	case ones == 0:
+		return models.SeverityCritical, true
+	case hostBits >= threshold+8:
+		return models.SeverityHigh, true
+	case hostBits >= threshold:
+		return models.SeverityMedium, true
+	default:
+		return "", false
+	}
+}
+
+func reportPolicy(policy models.ConditionalAccessPolicy, names *exclusionNamer) {
+	fmt.Printf("\n[INFO] Policy: %-50s State: %-35s Grant: %s\n", policy.DisplayName, policy.State, grantSummary(policy))
+
+	switch policy.State {
+	case policyStateReportOnly:
+		fmt.Println(models.Finding{
+			Severity: models.SeverityMedium,
+			Resource: policy.DisplayName,
+			Title:    "Policy is in report-only mode and is not enforced",
+			Evidence: "state=" + policy.State,
+		})
+	case policyStateEnabled:
+	default:
+		fmt.Println(models.Finding{
+			Severity: models.SeverityLow,
+			Resource: policy.DisplayName,
+			Title:    "Policy is disabled",
+			Evidence: "state=" + policy.State,
+		})
+	}
+
+	var control string
+	switch {
+	case requiresMFA(policy):
+		control = "MFA"
+	case blocksLegacyAuth(policy):
+		control = "legacy authentication block"
+	default:
+		return
+	}
+
+	// Exclusions from a policy nobody enforces are not a gap on their own
+	if policy.State != policyStateEnabled {
+		return
+	}
+
+	severity := models.SeverityMedium
+	if targetsAllUsers(policy) {
+		severity = models.SeverityHigh
+	}
+
+	for _, exclusion := range names.exclusions(policy) {
+		fmt.Println(models.Finding{
+			Severity: severity,
+			Resource: policy.DisplayName,
+			Title:    "Excluded from " + control + " policy",
+			Evidence: exclusion,
+		})
+	}
+}
+
+// reportTenantGaps reports controls that no enforced policy provides
+func reportTenantGaps(policies []models.ConditionalAccessPolicy) {
+	mfaForAll := false
+	legacyBlocked := false
+
+	for _, policy := range policies {
+		if policy.State != policyStateEnabled || !targetsAllUsers(policy) {
+			continue
+		}
+		if requiresMFA(policy) && targetsAllApps(policy) && targetsAllClients(policy) {
+			mfaForAll = true
+		}
+		if blocksLegacyAuth(policy) && targetsAllApps(policy) {
+			legacyBlocked = true
+		}
+	}
+
+	if !mfaForAll {
+		fmt.Println(models.Finding{
+			Severity: models.SeverityHigh,
+			Resource: "Tenant",
+			Title:    "No enforced policy requires MFA for all users and all cloud apps",
+			Evidence: fmt.Sprintf("%d policies evaluated", len(policies)),
+		})
+	}
+
+	if !legacyBlocked {
+		fmt.Println(models.Finding{
+			Severity: models.SeverityHigh,
+			Resource: "Tenant",
+			Title:    "Legacy authentication is not blocked for all users, password spraying bypasses MFA",
+			Evidence: "no enabled policy blocks clientAppTypes " + strings.Join(legacyClientAppTypes, ","),
+		})
+	}
+
+	if mfaForAll && legacyBlocked {
+		fmt.Println("[INFO] MFA is required and legacy authentication is blocked for all users.")
+	}
+}
+
+func grantSummary(policy models.ConditionalAccessPolicy) string {
+	if policy.GrantControls == nil {
+		return "session controls only"
+	}
+
+	controls := slices.Clone(policy.GrantControls.BuiltInControls)
+	if policy.GrantControls.AuthenticationStrength != nil {
+		controls = append(controls, "authStrength:"+policy.GrantControls.AuthenticationStrength.DisplayName)
+	}
+	return strings.Join(controls, " "+policy.GrantControls.Operator+" ")
+}
+
+func requiresMFA(policy models.ConditionalAccessPolicy) bool {
+	grant := policy.GrantControls
+	if grant == nil {
+		return false
+	}
+	return slices.Contains(grant.BuiltInControls, "mfa") || grant.AuthenticationStrength != nil
+}
+
+func blocksLegacyAuth(policy models.ConditionalAccessPolicy) bool {
+	if policy.GrantControls == nil || !slices.Contains(policy.GrantControls.BuiltInControls, "block") {
+		return false
+	}
+
+	for _, clientType := range legacyClientAppTypes {
+		if !slices.Contains(policy.Conditions.ClientAppTypes, clientType) {
+			return false
+		}
+	}
+	return true
+}
+
+func targetsAllUsers(policy models.ConditionalAccessPolicy) bool {
+	users := policy.Conditions.Users
+	return users != nil && slices.Contains(users.IncludeUsers, "All")
+}
+
+func targetsAllApps(policy models.ConditionalAccessPolicy) bool {
+	apps := policy.Conditions.Applications
+	return apps != nil && slices.Contains(apps.IncludeApplications, "All")
+}
+
+func targetsAllClients(policy models.ConditionalAccessPolicy) bool {
+	clients := policy.Conditions.ClientAppTypes
+	return len(clients) == 0 || slices.Contains(clients, "all")
+}
+
+// exclusionNamer turns the object IDs in policy exclusions into readable names
+type exclusionNamer struct {
+	principals map[string]models.Principal
+	roles      map[string]string
+	apps       map[string]string
+	locations  map[string]string
+}
+
+func newExclusionNamer(ctx context.Context, token string, policies []models.ConditionalAccessPolicy, locations map[string]string) *exclusionNamer {
+	namer := &exclusionNamer{
+		principals: make(map[string]models.Principal),
+		roles:      make(map[string]string),
+		apps:       make(map[string]string),
+		locations:  locations,
+	}
+
+	var ids []string
+	for _, policy := range policies {
+		if users := policy.Conditions.Users; users != nil {
+			ids = append(ids, users.ExcludeUsers...)
+			ids = append(ids, users.ExcludeGroups...)
+		}
+	}
+
+	// "GuestsOrExternalUsers" and friends are not object IDs
+	ids = slices.DeleteFunc(ids, func(id string) bool { return !strings.Contains(id, "-") })
+
+	if resolved, err := ResolvePrincipals(token, ids); err == nil {
+		namer.principals = resolved
+	} else {
+		fmt.Printf("[WARN] Could not resolve excluded principals: %v\n", err)
+	}
+
+	definitions, err := listAllGraphPages[models.DirectoryRoleDefinition](ctx, token,
+		graphBaseURL+"/roleManagement/directory/roleDefinitions?$select=id,displayName,templateId")
+	if err == nil {
+		for _, definition := range definitions {
+			namer.roles[definition.TemplateID] = definition.DisplayName
+		}
+	}
+
+	// Only tenant-owned apps are resolvable cheaply, first-party app IDs are shown as is
+	var servicePrincipals []models.GraphServicePrincipal
+	if found, _ := session.Load(ServicePrincipalsSession, &servicePrincipals); found {
+		for _, sp := range servicePrincipals {
+			namer.apps[sp.AppID] = sp.DisplayName
+		}
+	}
+
+	return namer
+}
+
+// exclusions lists every user, group, role, application and location excluded from a policy
+func (n *exclusionNamer) exclusions(policy models.ConditionalAccessPolicy) []string {
+	var exclusions []string
+
+	if users := policy.Conditions.Users; users != nil {
+		for _, id := range users.ExcludeUsers {
+			exclusions = append(exclusions, "user "+n.principal(id))
+		}
+		for _, id := range users.ExcludeGroups {
+			exclusions = append(exclusions, "group "+n.principal(id))
+		}
+		for _, id := range users.ExcludeRoles {
+			exclusions = append(exclusions, "role "+lookup(n.roles, id))
+		}
+	}
+
+	if apps := policy.Conditions.Applications; apps != nil {
+		for _, id := range apps.ExcludeApplications {
+			exclusions = append(exclusions, "application "+lookup(n.apps, id))
+		}
+	}
+
+	if locations := policy.Conditions.Locations; locations != nil {
+		for _, id := range locations.ExcludeLocations {
+			exclusions = append(exclusions, "location "+lookup(n.locations, id))
+		}
+	}
+
+	return exclusions
+}
+
+func (n *exclusionNamer) principal(id string) string {
+	if principal, ok := n.principals[id]; ok {
+		return principal.Label()
+	}
+	return id
+}
+
+func lookup(names map[string]string, id string) string {
+	if name, ok := names[id]; ok && name != "" {
+		return name + " (" + id + ")"
+	}
+	return id
+}
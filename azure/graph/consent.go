@@ -0,0 +1,292 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/f0rk3b0mb/GoCloudGhost/azure/auth"
+	"github.com/f0rk3b0mb/GoCloudGhost/azure/models"
+	"github.com/f0rk3b0mb/GoCloudGhost/session"
+	"github.com/spf13/cobra"
+)
+
+// ConsentSession is the session entry holding the consent grant audit
+const ConsentSession = "graph_consents"
+
+// highRiskDelegatedScopes are delegated scopes that expose mail, files or directory
+// write access of every consenting user, the usual targets of illicit consent grants
+var highRiskDelegatedScopes = map[string]string{
+	"RoleManagement.ReadWrite.Directory": models.SeverityCritical,
+	"AppRoleAssignment.ReadWrite.All":    models.SeverityCritical,
+	"Application.ReadWrite.All":          models.SeverityCritical,
+	"Directory.ReadWrite.All":            models.SeverityCritical,
+	"Directory.AccessAsUser.All":         models.SeverityHigh,
+	"User.ReadWrite.All":                 models.SeverityHigh,
+	"Group.ReadWrite.All":                models.SeverityHigh,
+	"Mail.ReadWrite":                     models.SeverityHigh,
+	"Mail.Send":                          models.SeverityHigh,
+	"MailboxSettings.ReadWrite":          models.SeverityHigh,
+	"EWS.AccessAsUser.All":               models.SeverityHigh,
+	"full_access_as_user":                models.SeverityHigh,
+	"Files.ReadWrite.All":                models.SeverityHigh,
+	"Sites.ReadWrite.All":                models.SeverityHigh,
+	"Sites.FullControl.All":              models.SeverityHigh,
+	"Mail.Read":                          models.SeverityMedium,
+	"Files.Read.All":                     models.SeverityMedium,
+	"Sites.Read.All":                     models.SeverityMedium,
+	"Notes.Read.All":                     models.SeverityMedium,
+	"Contacts.Read":                      models.SeverityLow,
+	"Calendars.Read":                     models.SeverityLow,
+}
+
+var consentCmd = &cobra.Command{
+	Use:   "consent",
+	Short: "Audit OAuth2 consent grants and application permissions of enterprise apps",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		token, err := tokenFromCmd(cmd)
+		if err != nil {
+			return err
+		}
+		return auditConsentGrants(token)
+	},
+}
+
+func auditConsentGrants(token string) error {
+	ctx := context.Background()
+
+	tenantID := ""
+	if claims, err := auth.ParseTokenClaims(token); err == nil {
+		tenantID = claims.TenantID
+	}
+
+	servicePrincipals, err := listAllGraphPages[models.GraphServicePrincipal](ctx, token,
+		graphBaseURL+"/servicePrincipals?$top=999&$select=id,appId,displayName,appOwnerOrganizationId,publisherName,verifiedPublisher,appRoles")
+	if err != nil {
+		return err
+	}
+
+	byID := make(map[string]models.GraphServicePrincipal, len(servicePrincipals))
+	for _, sp := range servicePrincipals {
+		byID[sp.ID] = sp
+	}
+
+	consents := make(map[string]*models.AppConsent)
+	consentFor := func(id string) *models.AppConsent {
+		if consent, ok := consents[id]; ok {
+			return consent
+		}
+		sp := byID[id]
+		consent := &models.AppConsent{
+			ObjectID:      id,
+			AppID:         sp.AppID,
+			DisplayName:   sp.DisplayName,
+			Publisher:     publisher(sp),
+			External:      sp.AppOwnerOrganizationID != "" && sp.AppOwnerOrganizationID != tenantID,
+			UserConsented: make(map[string][]string),
+		}
+		if consent.DisplayName == "" {
+			consent.DisplayName = id
+		}
+		consents[id] = consent
+		return consent
+	}
+
+	grants, err := listAllGraphPages[models.OAuth2PermissionGrant](ctx, token, graphBaseURL+"/oauth2PermissionGrants?$top=999")
+	if err != nil {
+		return err
+	}
+
+	var consentingUsers []string
+	for _, grant := range grants {
+		consent := consentFor(grant.ClientID)
+		resource := byID[grant.ResourceID].DisplayName
+
+		for _, scope := range strings.Fields(grant.Scope) {
+			permission := resource + "/" + scope
+			if grant.ConsentType == "AllPrincipals" {
+				consent.AdminConsented = append(consent.AdminConsented, permission)
+				continue
+			}
+			consent.UserConsented[permission] = append(consent.UserConsented[permission], grant.PrincipalID)
+		}
+		consentingUsers = append(consentingUsers, grant.PrincipalID)
+	}
+
+	// Application permissions are app roles of a resource, so only service principals
+	// that expose app roles can have them assigned
+	for _, resource := range servicePrincipals {
+		if len(resource.AppRoles) == 0 {
+			continue
+		}
+
+		url := fmt.Sprintf("%s/servicePrincipals/%s/appRoleAssignedTo?$top=999", graphBaseURL, resource.ID)
+		assignments, err := listAllGraphPages[models.AppRoleAssignment](ctx, token, url)
+		if err != nil {
+			fmt.Printf("[WARN] appRoleAssignedTo request failed for %s: %v\n", resource.DisplayName, err)
+			continue
+		}
+
+		for _, assignment := range assignments {
+			if assignment.PrincipalType != "ServicePrincipal" {
+				continue
+			}
+			consent := consentFor(assignment.PrincipalID)
+			consent.ApplicationPermissions = append(consent.ApplicationPermissions,
+				resource.DisplayName+"/"+appRoleValue(resource, assignment.AppRoleID))
+		}
+	}
+
+	users, err := ResolvePrincipals(token, consentingUsers)
+	if err != nil {
+		fmt.Printf("[WARN] Could not resolve consenting users: %v\n", err)
+	}
+
+	ordered := make([]models.AppConsent, 0, len(consents))
+	for _, consent := range consents {
+		ordered = append(ordered, *consent)
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		return ordered[i].DisplayName < ordered[j].DisplayName
+	})
+
+	fmt.Println("\n=== CONSENT GRANTS ===")
+
+	if len(ordered) == 0 {
+		fmt.Println("[INFO] No consent grants or application permissions found.")
+	}
+
+	for _, consent := range ordered {
+		reportAppConsent(consent, users)
+	}
+
+	return session.Save(ConsentSession, ordered)
+}
+
+func reportAppConsent(consent models.AppConsent, users map[string]models.Principal) {
+	fmt.Printf("\n[INFO] App: %-40s App ID: %-38s Publisher: %s  Delegated: %d  Application: %d\n",
+		consent.DisplayName,
+		consent.AppID,
+		consent.Publisher,
+		len(consent.AdminConsented)+len(consent.UserConsented),
+		len(consent.ApplicationPermissions),
+	)
+
+	highest := ""
+	raise := func(severity string) {
+		if models.SeverityRank(severity) > models.SeverityRank(highest) {
+			highest = severity
+		}
+	}
+
+	admin := uniqueStrings(consent.AdminConsented)
+	if len(admin) > 0 {
+		fmt.Printf("    Delegated (admin consent, all users): %s\n", strings.Join(admin, ", "))
+	}
+	for _, permission := range admin {
+		severity, ok := highRiskDelegatedScopes[permissionName(permission)]
+		if !ok {
+			continue
+		}
+		raise(severity)
+		fmt.Println(models.Finding{
+			Severity: severity,
+			Resource: consent.DisplayName,
+			Title:    "Tenant-wide admin consent to high-risk delegated scope",
+			Evidence: permission,
+		})
+	}
+
+	permissions := make([]string, 0, len(consent.UserConsented))
+	for permission := range consent.UserConsented {
+		permissions = append(permissions, permission)
+	}
+	sort.Strings(permissions)
+
+	for _, permission := range permissions {
+		consenting := uniqueStrings(consent.UserConsented[permission])
+		fmt.Printf("    Delegated (user consent): %-40s Users: %d\n", permission, len(consenting))
+
+		severity, ok := highRiskDelegatedScopes[permissionName(permission)]
+		if !ok {
+			continue
+		}
+		raise(severity)
+
+		labels := make([]string, 0, len(consenting))
+		for _, id := range consenting {
+			if user, ok := users[id]; ok {
+				labels = append(labels, user.Label())
+			} else {
+				labels = append(labels, id)
+			}
+		}
+		fmt.Println(models.Finding{
+			Severity: severity,
+			Resource: consent.DisplayName,
+			Title:    "Users consented to high-risk delegated scope",
+			Evidence: permission + " by " + strings.Join(labels, ", "),
+		})
+	}
+
+	for _, permission := range uniqueStrings(consent.ApplicationPermissions) {
+		fmt.Printf("    Application: %s\n", permission)
+
+		severity, ok := highRiskAppPermissions[permissionName(permission)]
+		if !ok {
+			continue
+		}
+		raise(severity)
+		fmt.Println(models.Finding{
+			Severity: severity,
+			Resource: consent.DisplayName,
+			Title:    "High-risk application permission granted",
+			Evidence: permission,
+		})
+	}
+
+	// Third-party apps nobody verified holding sensitive grants are the signature of consent phishing
+	if highest != "" && consent.External && consent.Publisher == "unverified" {
+		fmt.Println(models.Finding{
+			Severity: models.SeverityHigh,
+			Resource: consent.DisplayName,
+			Title:    "Multi-tenant app from an unverified publisher holds high-risk permissions",
+			Evidence: "appId=" + consent.AppID,
+		})
+	}
+}
+
+// publisher describes who publishes an app: Microsoft, a verified publisher or unverified
+func publisher(sp models.GraphServicePrincipal) string {
+	switch {
+	case sp.AppOwnerOrganizationID == microsoftTenantID:
+		return "Microsoft"
+	case sp.VerifiedPublisher != nil && sp.VerifiedPublisher.DisplayName != "":
+		return sp.VerifiedPublisher.DisplayName + " (verified)"
+	default:
+		return "unverified"
+	}
+}
+
+func appRoleValue(resource models.GraphServicePrincipal, appRoleID string) string {
+	for _, role := range resource.AppRoles {
+		if role.ID == appRoleID {
+			return role.Value
+		}
+	}
+	return appRoleID
+}
+
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	var unique []string
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	sort.Strings(unique)
+	return unique
+}
@@ -0,0 +1,281 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/f0rk3b0mb/GoCloudGhost/azure/auth"
+	"github.com/f0rk3b0mb/GoCloudGhost/azure/models"
+	"github.com/f0rk3b0mb/GoCloudGhost/session"
+	"github.com/spf13/cobra"
+)
+
+// AppAuditSession is the session entry holding the application credential audit
+const AppAuditSession = "graph_app_audit"
+
+// microsoftTenantID owns Microsoft's first-party service principals, which we cannot modify
+const microsoftTenantID = "f8cdef31-a31e-4b4a-93e4-5f571e91255a"
+
+// highRiskAppPermissions are application permissions that allow taking over the tenant
+// or large parts of it when held by a service principal we control
+var highRiskAppPermissions = map[string]string{
+	"RoleManagement.ReadWrite.Directory":          models.SeverityCritical,
+	"AppRoleAssignment.ReadWrite.All":             models.SeverityCritical,
+	"Application.ReadWrite.All":                   models.SeverityCritical,
+	"Directory.ReadWrite.All":                     models.SeverityCritical,
+	"ServicePrincipalEndpoint.ReadWrite.All":      models.SeverityHigh,
+	"Group.ReadWrite.All":                         models.SeverityHigh,
+	"GroupMember.ReadWrite.All":                   models.SeverityHigh,
+	"User.ReadWrite.All":                          models.SeverityHigh,
+	"UserAuthenticationMethod.ReadWrite.All":      models.SeverityHigh,
+	"Policy.ReadWrite.ConditionalAccess":          models.SeverityHigh,
+	"Policy.ReadWrite.AuthenticationMethod":       models.SeverityHigh,
+	"DeviceManagementConfiguration.ReadWrite.All": models.SeverityHigh,
+	"Mail.ReadWrite":                              models.SeverityHigh,
+	"Mail.Read":                                   models.SeverityMedium,
+	"Files.ReadWrite.All":                         models.SeverityHigh,
+	"Sites.ReadWrite.All":                         models.SeverityHigh,
+	"Sites.FullControl.All":                       models.SeverityHigh,
+}
+
+var credentialsCmd = &cobra.Command{
+	Use:   "credentials",
+	Short: "Audit app registration and service principal credentials, owners and application permissions",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		token, err := tokenFromCmd(cmd)
+		if err != nil {
+			return err
+		}
+		return auditAppCredentials(token)
+	},
+}
+
+func auditAppCredentials(token string) error {
+	ctx := context.Background()
+
+	currentID := ""
+	if claims, err := auth.ParseTokenClaims(token); err == nil {
+		currentID = claims.ObjectID
+	}
+
+	applications, err := listAllGraphPages[models.GraphApplication](ctx, token,
+		graphBaseURL+"/applications?$top=999&$select=id,appId,displayName,signInAudience,passwordCredentials,keyCredentials")
+	if err != nil {
+		return err
+	}
+
+	servicePrincipals, err := listAllGraphPages[models.GraphServicePrincipal](ctx, token,
+		graphBaseURL+"/servicePrincipals?$top=999&$select=id,appId,displayName,servicePrincipalType,appOwnerOrganizationId,passwordCredentials,keyCredentials")
+	if err != nil {
+		return err
+	}
+
+	resolver := newAppRoleResolver()
+	var audits []models.AppAudit
+
+	// Application permissions are granted to the service principal, index them by appId
+	// so an app registration can be reported with the permissions its credentials unlock
+	permissionsByAppID := make(map[string][]string)
+
+	fmt.Println("\n=== SERVICE PRINCIPAL CREDENTIALS ===")
+
+	for _, sp := range servicePrincipals {
+		if sp.AppOwnerOrganizationID == microsoftTenantID {
+			continue
+		}
+
+		audit := models.AppAudit{
+			ObjectID:             sp.ID,
+			AppID:                sp.AppID,
+			DisplayName:          sp.DisplayName,
+			Kind:                 models.AppAuditKindServicePrincipal,
+			ServicePrincipalType: sp.ServicePrincipalType,
+			PasswordCredentials:  sp.PasswordCredentials,
+			KeyCredentials:       sp.KeyCredentials,
+		}
+
+		audit.AppPermissions, err = resolver.applicationPermissions(ctx, token, sp.ID)
+		if err != nil {
+			fmt.Printf("[WARN] App role assignment request failed for %s: %v\n", sp.DisplayName, err)
+		}
+		permissionsByAppID[sp.AppID] = audit.AppPermissions
+
+		audit.Owners, err = listOwners(ctx, token, "servicePrincipals", sp.ID)
+		if err != nil {
+			fmt.Printf("[WARN] Owner request failed for %s: %v\n", sp.DisplayName, err)
+		}
+
+		reportAppAudit(audit, audit.AppPermissions, currentID)
+		audits = append(audits, audit)
+	}
+
+	fmt.Println("\n=== APP REGISTRATION CREDENTIALS ===")
+
+	for _, application := range applications {
+		audit := models.AppAudit{
+			ObjectID:            application.ID,
+			AppID:               application.AppID,
+			DisplayName:         application.DisplayName,
+			Kind:                models.AppAuditKindApplication,
+			PasswordCredentials: application.PasswordCredentials,
+			KeyCredentials:      application.KeyCredentials,
+		}
+
+		audit.Owners, err = listOwners(ctx, token, "applications", application.ID)
+		if err != nil {
+			fmt.Printf("[WARN] Owner request failed for %s: %v\n", application.DisplayName, err)
+		}
+
+		reportAppAudit(audit, permissionsByAppID[application.AppID], currentID)
+		audits = append(audits, audit)
+	}
+
+	return session.Save(AppAuditSession, audits)
+}
+
+// reportAppAudit prints an audit entry. permissions are the application permissions
+// reachable through the entry's credentials, which for an app registration are those
+// of its service principal.
+func reportAppAudit(audit models.AppAudit, permissions []string, currentID string) {
+	fmt.Printf("\n[INFO] %s: %-40s App ID: %s\n", audit.Kind, audit.DisplayName, audit.AppID)
+
+	for _, secret := range audit.PasswordCredentials {
+		fmt.Printf("    Secret: %-25s Hint: %-5s Expires: %s\n", secret.DisplayName, secret.Hint, credentialExpiry(secret.EndDateTime))
+	}
+	for _, key := range audit.KeyCredentials {
+		fmt.Printf("    Certificate: %-20s Type: %-18s Usage: %-8s Expires: %s\n", key.DisplayName, key.Type, key.Usage, credentialExpiry(key.EndDateTime))
+	}
+
+	ownedByCurrent := false
+	for _, owner := range audit.Owners {
+		fmt.Printf("    Owner: %s (%s)\n", owner.Label(), owner.Type)
+		if currentID != "" && owner.ID == currentID {
+			ownedByCurrent = true
+		}
+	}
+
+	highest := ""
+	for _, permission := range permissions {
+		fmt.Printf("    App Permission: %s\n", permission)
+
+		severity, ok := highRiskAppPermissions[permissionName(permission)]
+		if !ok {
+			continue
+		}
+		if models.SeverityRank(severity) > models.SeverityRank(highest) {
+			highest = severity
+		}
+
+		// Only report the grant once, on the service principal that holds it
+		if audit.Kind == models.AppAuditKindServicePrincipal {
+			fmt.Println(models.Finding{
+				Severity: severity,
+				Resource: audit.DisplayName,
+				Title:    "High-risk application permission granted",
+				Evidence: permission,
+			})
+		}
+	}
+
+	if audit.Kind == models.AppAuditKindServicePrincipal && len(audit.PasswordCredentials) > 0 {
+		fmt.Println(models.Finding{
+			Severity: models.SeverityMedium,
+			Resource: audit.DisplayName,
+			Title:    "Service principal has its own client secrets, which the portal does not show",
+			Evidence: fmt.Sprintf("%d passwordCredentials", len(audit.PasswordCredentials)),
+		})
+	}
+
+	if ownedByCurrent {
+		severity := models.SeverityHigh
+		if models.SeverityRank(highest) > models.SeverityRank(severity) {
+			severity = highest
+		}
+		fmt.Println(models.Finding{
+			Severity: severity,
+			Resource: audit.DisplayName,
+			Title:    "Current principal owns this " + audit.Kind + " and can add credentials to it",
+			Evidence: fmt.Sprintf("owner=%s appPermissions=%d", currentID, len(permissions)),
+		})
+	}
+}
+
+func credentialExpiry(end string) string {
+	expiry, err := time.Parse(time.RFC3339, end)
+	if err != nil {
+		return end
+	}
+
+	if time.Now().After(expiry) {
+		return expiry.Format("2006-01-02") + " (expired)"
+	}
+	return expiry.Format("2006-01-02")
+}
+
+// permissionName strips the resource prefix from a "Resource/Permission" string
+func permissionName(permission string) string {
+	return permission[strings.LastIndex(permission, "/")+1:]
+}
+
+func listOwners(ctx context.Context, token, collection, id string) ([]models.Principal, error) {
+	url := fmt.Sprintf("%s/%s/%s/owners?$select=id,displayName,userPrincipalName,appId,servicePrincipalType", graphBaseURL, collection, id)
+
+	objects, err := listAllGraphPages[models.DirectoryObject](ctx, token, url)
+	if err != nil {
+		return nil, err
+	}
+
+	owners := make([]models.Principal, 0, len(objects))
+	for _, object := range objects {
+		owners = append(owners, principalFromObject(object))
+	}
+	return owners, nil
+}
+
+// appRoleResolver maps app role IDs to permission names, caching each resource service principal
+type appRoleResolver struct {
+	resources map[string]models.GraphServicePrincipal
+}
+
+func newAppRoleResolver() *appRoleResolver {
+	return &appRoleResolver{resources: make(map[string]models.GraphServicePrincipal)}
+}
+
+// applicationPermissions returns the application permissions granted to a service principal as "Resource/Permission"
+func (r *appRoleResolver) applicationPermissions(ctx context.Context, token, servicePrincipalID string) ([]string, error) {
+	url := fmt.Sprintf("%s/servicePrincipals/%s/appRoleAssignments", graphBaseURL, servicePrincipalID)
+
+	assignments, err := listAllGraphPages[models.AppRoleAssignment](ctx, token, url)
+	if err != nil {
+		return nil, err
+	}
+
+	var permissions []string
+	for _, assignment := range assignments {
+		permissions = append(permissions, assignment.ResourceDisplayName+"/"+r.roleValue(ctx, token, assignment.ResourceID, assignment.AppRoleID))
+	}
+	return permissions, nil
+}
+
+func (r *appRoleResolver) roleValue(ctx context.Context, token, resourceID, appRoleID string) string {
+	resource, ok := r.resources[resourceID]
+	if !ok {
+		url := fmt.Sprintf("%s/servicePrincipals/%s?$select=id,appId,displayName,appRoles", graphBaseURL, resourceID)
+		if err := makeGraphRequest(ctx, token, http.MethodGet, url, nil, &resource); err != nil {
+			return appRoleID
+		}
+		r.resources[resourceID] = resource
+	}
+
+	for _, role := range resource.AppRoles {
+		if role.ID == appRoleID {
+			return role.Value
+		}
+	}
+
+	// Unknown IDs, such as the all-zero default access role, are shown as is
+	return appRoleID
+}
@@ -0,0 +1,222 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/f0rk3b0mb/GoCloudGhost/azure/models"
+	"github.com/f0rk3b0mb/GoCloudGhost/session"
+	"github.com/spf13/cobra"
+)
+
+// Session entries holding the directory objects collected by the graph commands
+const (
+	UsersSession             = "graph_users"
+	GroupsSession            = "graph_groups"
+	ServicePrincipalsSession = "graph_serviceprincipals"
+	ApplicationsSession      = "graph_applications"
+)
+
+var usersCmd = &cobra.Command{
+	Use:   "users",
+	Short: "Enumerate users with guest status, sign-in activity and account state",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		token, err := tokenFromCmd(cmd)
+		if err != nil {
+			return err
+		}
+		return enumerateUsers(token)
+	},
+}
+
+var groupsCmd = &cobra.Command{
+	Use:   "groups",
+	Short: "Enumerate groups with role-assignable flags and dynamic membership rules",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		token, err := tokenFromCmd(cmd)
+		if err != nil {
+			return err
+		}
+		withMembers, _ := cmd.Flags().GetBool("members")
+		return enumerateGroups(token, withMembers)
+	},
+}
+
+func init() {
+	groupsCmd.Flags().Bool("members", false, "Also list the direct members of every group")
+}
+
+var servicePrincipalsCmd = &cobra.Command{
+	Use:   "serviceprincipals",
+	Short: "Enumerate service principals and managed identities",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		token, err := tokenFromCmd(cmd)
+		if err != nil {
+			return err
+		}
+		return enumerateServicePrincipals(token)
+	},
+}
+
+var applicationsCmd = &cobra.Command{
+	Use:   "apps",
+	Short: "Enumerate app registrations",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		token, err := tokenFromCmd(cmd)
+		if err != nil {
+			return err
+		}
+		return enumerateApplications(token)
+	},
+}
+
+func enumerateUsers(token string) error {
+	ctx := context.Background()
+
+	fields := "id,displayName,userPrincipalName,mail,userType,accountEnabled,createdDateTime"
+
+	// signInActivity needs AuditLog.Read.All and a premium licence, retry without it when refused
+	users, err := listAllGraphPages[models.GraphUser](ctx, token, graphBaseURL+"/users?$top=999&$select="+fields+",signInActivity")
+	if err != nil {
+		fmt.Printf("[WARN] Sign-in activity unavailable, listing users without it: %v\n", err)
+		users, err = listAllGraphPages[models.GraphUser](ctx, token, graphBaseURL+"/users?$top=999&$select="+fields)
+		if err != nil {
+			return err
+		}
+	}
+
+	fmt.Println("\n=== USERS ===")
+
+	for _, user := range users {
+		lastSignIn := "unknown"
+		if user.SignInActivity != nil && user.SignInActivity.LastSignInDateTime != "" {
+			lastSignIn = user.SignInActivity.LastSignInDateTime
+		}
+
+		fmt.Printf("[INFO] User: %-45s Name: %-30s Type: %-6s Enabled: %-5t Last Sign-In: %s\n",
+			user.UserPrincipalName,
+			user.DisplayName,
+			user.UserType,
+			user.AccountEnabled,
+			lastSignIn,
+		)
+	}
+
+	fmt.Printf("[INFO] %d users\n", len(users))
+
+	return session.Save(UsersSession, users)
+}
+
+func enumerateGroups(token string, withMembers bool) error {
+	ctx := context.Background()
+
+	url := graphBaseURL + "/groups?$top=999&$select=id,displayName,description,groupTypes,securityEnabled,mailEnabled,isAssignableToRole,membershipRule,membershipRuleProcessingState"
+
+	groups, err := listAllGraphPages[models.GraphGroup](ctx, token, url)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("\n=== GROUPS ===")
+
+	for i, group := range groups {
+		kind := "Assigned"
+		if group.MembershipRule != "" {
+			kind = "Dynamic"
+		}
+
+		fmt.Printf("[INFO] Group: %-40s Membership: %-8s Security: %-5t Role Assignable: %t\n",
+			group.DisplayName,
+			kind,
+			group.SecurityEnabled,
+			group.IsAssignableToRole,
+		)
+
+		if group.MembershipRule != "" {
+			fmt.Printf("    Rule (%s): %s\n", group.MembershipRuleProcessingState, group.MembershipRule)
+		}
+
+		if !withMembers {
+			continue
+		}
+
+		groups[i].Members, err = listMembers(ctx, token, group.ID)
+		if err != nil {
+			fmt.Printf("[WARN] Member request failed for %s: %v\n", group.DisplayName, err)
+			continue
+		}
+		for _, member := range groups[i].Members {
+			fmt.Printf("    Member: %s (%s)\n", member.Label(), member.Type)
+		}
+	}
+
+	fmt.Printf("[INFO] %d groups\n", len(groups))
+
+	return session.Save(GroupsSession, groups)
+}
+
+func listMembers(ctx context.Context, token, groupID string) ([]models.Principal, error) {
+	url := fmt.Sprintf("%s/groups/%s/members?$top=999&$select=id,displayName,userPrincipalName,appId,servicePrincipalType", graphBaseURL, groupID)
+
+	objects, err := listAllGraphPages[models.DirectoryObject](ctx, token, url)
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]models.Principal, 0, len(objects))
+	for _, object := range objects {
+		members = append(members, principalFromObject(object))
+	}
+	return members, nil
+}
+
+func enumerateServicePrincipals(token string) error {
+	ctx := context.Background()
+
+	url := graphBaseURL + "/servicePrincipals?$top=999&$select=id,appId,displayName,servicePrincipalType,accountEnabled,appOwnerOrganizationId"
+
+	principals, err := listAllGraphPages[models.GraphServicePrincipal](ctx, token, url)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("\n=== SERVICE PRINCIPALS ===")
+
+	for _, principal := range principals {
+		fmt.Printf("[INFO] Service Principal: %-40s Type: %-16s App ID: %s  Enabled: %t\n",
+			principal.DisplayName,
+			principal.ServicePrincipalType,
+			principal.AppID,
+			principal.AccountEnabled,
+		)
+	}
+
+	fmt.Printf("[INFO] %d service principals\n", len(principals))
+
+	return session.Save(ServicePrincipalsSession, principals)
+}
+
+func enumerateApplications(token string) error {
+	ctx := context.Background()
+
+	url := graphBaseURL + "/applications?$top=999&$select=id,appId,displayName,signInAudience,createdDateTime"
+
+	applications, err := listAllGraphPages[models.GraphApplication](ctx, token, url)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("\n=== APP REGISTRATIONS ===")
+
+	for _, application := range applications {
+		fmt.Printf("[INFO] Application: %-40s App ID: %s  Audience: %s\n",
+			application.DisplayName,
+			application.AppID,
+			application.SignInAudience,
+		)
+	}
+
+	fmt.Printf("[INFO] %d app registrations\n", len(applications))
+
+	return session.Save(ApplicationsSession, applications)
+}
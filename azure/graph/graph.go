@@ -0,0 +1,29 @@
+package graph
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var GraphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Enumerate Entra ID through Microsoft Graph",
+}
+
+func init() {
+	GraphCmd.PersistentFlags().String("graph-token", "", "Microsoft Graph access token")
+
+	GraphCmd.AddCommand(usersCmd)
+	GraphCmd.AddCommand(groupsCmd)
+	GraphCmd.AddCommand(servicePrincipalsCmd)
+	GraphCmd.AddCommand(applicationsCmd)
+	GraphCmd.AddCommand(credentialsCmd)
+	GraphCmd.AddCommand(rolesCmd)
+	GraphCmd.AddCommand(conditionalAccessCmd)
+	GraphCmd.AddCommand(consentCmd)
+}
+
+// tokenFromCmd loads the Graph token from the --graph-token flag or the environment
+func tokenFromCmd(cmd *cobra.Command) (string, error) {
+	cliToken, _ := cmd.Flags().GetString("graph-token")
+	return LoadToken(cliToken)
+}
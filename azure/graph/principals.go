@@ -0,0 +1,117 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/f0rk3b0mb/GoCloudGhost/azure/models"
+	"github.com/f0rk3b0mb/GoCloudGhost/session"
+)
+
+// PrincipalsSession is the session entry holding every principal resolved so far
+const PrincipalsSession = "principals"
+
+// getByIds accepts at most 1000 IDs per request
+const getByIdsBatchSize = 1000
+
+// ResolvePrincipals resolves object IDs to principals using directoryObjects/getByIds.
+// Results are cached in the session so each ID is only looked up once. IDs Graph does not
+// return are reported as unknown but not cached, since they may only be hidden from the
+// current token.
+func ResolvePrincipals(token string, ids []string) (map[string]models.Principal, error) {
+	cache := make(map[string]models.Principal)
+	if _, err := session.Load(PrincipalsSession, &cache); err != nil {
+		return nil, err
+	}
+
+	var missing []string
+	seen := make(map[string]bool)
+	for _, id := range ids {
+		if _, ok := cache[id]; ok || seen[id] || id == "" {
+			continue
+		}
+		seen[id] = true
+		missing = append(missing, id)
+	}
+
+	if len(missing) == 0 {
+		return cache, nil
+	}
+
+	ctx := context.Background()
+	for start := 0; start < len(missing); start += getByIdsBatchSize {
+		end := min(start+getByIdsBatchSize, len(missing))
+
+		objects, err := getByIds(ctx, token, missing[start:end])
+		if err != nil {
+			// Keep the batches that did resolve
+			if saveErr := session.Save(PrincipalsSession, cache); saveErr != nil {
+				return cache, saveErr
+			}
+			return cache, err
+		}
+
+		for _, object := range objects {
+			cache[object.ID] = principalFromObject(object)
+		}
+	}
+
+	if err := session.Save(PrincipalsSession, cache); err != nil {
+		return cache, err
+	}
+
+	// Deleted principals and ones the token cannot read are returned as unknown
+	resolved := make(map[string]models.Principal, len(cache)+len(missing))
+	for id, principal := range cache {
+		resolved[id] = principal
+	}
+	for _, id := range missing {
+		if _, ok := resolved[id]; !ok {
+			resolved[id] = models.Principal{ID: id, Type: models.PrincipalTypeUnknown}
+		}
+	}
+
+	return resolved, nil
+}
+
+func getByIds(ctx context.Context, token string, ids []string) ([]models.DirectoryObject, error) {
+	body := map[string]interface{}{
+		"ids":   ids,
+		"types": []string{"user", "group", "servicePrincipal"},
+	}
+
+	var result struct {
+		Value []models.DirectoryObject `json:"value"`
+	}
+	if err := makeGraphRequest(ctx, token, http.MethodPost, graphBaseURL+"/directoryObjects/getByIds", body, &result); err != nil {
+		return nil, fmt.Errorf("failed to resolve principals: %w", err)
+	}
+
+	return result.Value, nil
+}
+
+func principalFromObject(object models.DirectoryObject) models.Principal {
+	principal := models.Principal{
+		ID:          object.ID,
+		DisplayName: object.DisplayName,
+	}
+
+	switch object.ODataType {
+	case "#microsoft.graph.user":
+		principal.Type = models.PrincipalTypeUser
+		principal.Identifier = object.UserPrincipalName
+	case "#microsoft.graph.group":
+		principal.Type = models.PrincipalTypeGroup
+	case "#microsoft.graph.servicePrincipal":
+		principal.Type = models.PrincipalTypeServicePrincipal
+		if object.ServicePrincipalType == "ManagedIdentity" {
+			principal.Type = models.PrincipalTypeManagedIdentity
+		}
+		principal.Identifier = object.AppID
+	default:
+		principal.Type = models.PrincipalTypeUnknown
+	}
+
+	return principal
+}
@@ -0,0 +1,222 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/f0rk3b0mb/GoCloudGhost/azure/auth"
+	"github.com/f0rk3b0mb/GoCloudGhost/azure/models"
+	"github.com/f0rk3b0mb/GoCloudGhost/session"
+	"github.com/spf13/cobra"
+)
+
+// DirectoryRolesSession is the session entry holding resolved directory role assignments
+const DirectoryRolesSession = "graph_directory_roles"
+
+// GlobalAdministratorTemplateID is the role template ID of Global Administrator
+const GlobalAdministratorTemplateID = "62e90394-69f5-4237-9190-012177145e10"
+
+// privilegedDirectoryRoles are role template IDs that allow escalating to Global
+// Administrator or taking over other privileged identities
+var privilegedDirectoryRoles = map[string]string{
+	GlobalAdministratorTemplateID:          models.SeverityCritical, // Global Administrator
+	"e8611ab8-c189-46e8-94e1-60213ab1f814": models.SeverityCritical, // Privileged Role Administrator
+	"7be44c8a-adaf-4e2a-84d6-ab2649e08a13": models.SeverityCritical, // Privileged Authentication Administrator
+	"9b895d92-2cd3-44c7-9d02-a6ac2d5ea5c3": models.SeverityHigh,     // Application Administrator
+	"158c047a-c907-4556-b7ef-446551a6b5f7": models.SeverityHigh,     // Cloud Application Administrator
+	"8ac3fc64-6eca-42ea-9e69-59f4c7b60eb2": models.SeverityHigh,     // Hybrid Identity Administrator
+	"b1be1c3e-b65d-4f19-8427-f6fa0d97feb9": models.SeverityHigh,     // Conditional Access Administrator
+	"c4e39bd9-1100-46d3-8c65-fb160da0071f": models.SeverityHigh,     // Authentication Administrator
+	"fe930be7-5e62-47db-91af-98c3a49a38b1": models.SeverityHigh,     // User Administrator
+	"fdd7a751-b60b-444a-984c-02652fe8fa1c": models.SeverityHigh,     // Groups Administrator
+	"194ae4cb-b126-40b2-bd5b-6091b380977d": models.SeverityHigh,     // Security Administrator
+	"3a2c62db-5318-420d-8d74-23affee5d9d5": models.SeverityHigh,     // Intune Administrator
+	"29232cdf-9323-42fd-ade2-1d097af3e4de": models.SeverityHigh,     // Exchange Administrator
+	"f28a1f50-f6e7-4571-818b-6a12f2af6b6c": models.SeverityHigh,     // SharePoint Administrator
+	"d29b2b05-8046-44ba-8758-1e26182fcf32": models.SeverityHigh,     // Directory Synchronization Accounts
+	"9f06204d-73c1-4d4c-880a-6edb90606fd8": models.SeverityMedium,   // Azure AD Joined Device Local Administrator
+}
+
+var rolesCmd = &cobra.Command{
+	Use:   "roles",
+	Short: "Enumerate directory role assignments, including administrative unit scoped ones",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		token, err := tokenFromCmd(cmd)
+		if err != nil {
+			return err
+		}
+		return enumerateDirectoryRoles(token)
+	},
+}
+
+func enumerateDirectoryRoles(token string) error {
+	ctx := context.Background()
+
+	claims, err := auth.ParseTokenClaims(token)
+	if err != nil {
+		fmt.Printf("[WARN] Could not decode token claims: %v\n", err)
+		claims = &auth.TokenClaims{}
+	}
+
+	definitions, err := listAllGraphPages[models.DirectoryRoleDefinition](ctx, token,
+		graphBaseURL+"/roleManagement/directory/roleDefinitions?$select=id,displayName,templateId,isBuiltIn")
+	if err != nil {
+		return err
+	}
+
+	roleNames := make(map[string]string, len(definitions))
+	templateIDs := make(map[string]string, len(definitions))
+	for _, definition := range definitions {
+		roleNames[definition.ID] = definition.DisplayName
+		roleNames[definition.TemplateID] = definition.DisplayName
+		templateIDs[definition.ID] = definition.TemplateID
+	}
+
+	scopeNames := map[string]string{"/": "Tenant"}
+	units, err := listAllGraphPages[models.AdministrativeUnit](ctx, token,
+		graphBaseURL+"/directory/administrativeUnits?$select=id,displayName,isMemberManagementRestricted")
+	if err != nil {
+		fmt.Printf("[WARN] Administrative unit request failed: %v\n", err)
+	}
+
+	if len(units) > 0 {
+		fmt.Println("\n=== ADMINISTRATIVE UNITS ===")
+	}
+	for _, unit := range units {
+		scopeNames["/administrativeUnits/"+unit.ID] = "AU " + unit.DisplayName
+		fmt.Printf("[INFO] Administrative Unit: %-40s Restricted Management: %t\n", unit.DisplayName, unit.IsMemberManagementRestricted)
+	}
+
+	assignments, err := listRoleAssignments(ctx, token, roleNames, templateIDs, scopeNames)
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(assignments, func(i, j int) bool {
+		if assignments[i].RoleName != assignments[j].RoleName {
+			return assignments[i].RoleName < assignments[j].RoleName
+		}
+		return assignments[i].Principal.Label() < assignments[j].Principal.Label()
+	})
+
+	fmt.Println("\n=== DIRECTORY ROLE ASSIGNMENTS ===")
+
+	for _, assignment := range assignments {
+		level := "[INFO]"
+		if severity, ok := privilegedDirectoryRoles[assignment.RoleTemplateID]; ok {
+			level = "[" + severity + "]"
+		}
+
+		marker := ""
+		if claims.ObjectID != "" && assignment.Principal.ID == claims.ObjectID {
+			marker = "  <- current principal"
+		}
+
+		fmt.Printf("%s Role: %-40s Principal: %-50s Type: %-16s Scope: %s%s\n",
+			level,
+			assignment.RoleName,
+			assignment.Principal.Label(),
+			assignment.Principal.Type,
+			assignment.ScopeName,
+			marker,
+		)
+	}
+
+	reportTokenDirectoryRoles(claims, roleNames)
+
+	return session.Save(DirectoryRolesSession, assignments)
+}
+
+// listRoleAssignments returns resolved directory role assignments. It prefers the unified
+// role management API, which includes administrative unit scopes, and falls back to the
+// members of activated directoryRoles when the token cannot read it.
+func listRoleAssignments(ctx context.Context, token string, roleNames, templateIDs, scopeNames map[string]string) ([]models.DirectoryRoleAssignment, error) {
+	unified, err := listAllGraphPages[models.UnifiedRoleAssignment](ctx, token,
+		graphBaseURL+"/roleManagement/directory/roleAssignments?$expand=principal")
+	if err == nil {
+		assignments := make([]models.DirectoryRoleAssignment, 0, len(unified))
+		for _, assignment := range unified {
+			principal := models.Principal{ID: assignment.PrincipalID, Type: models.PrincipalTypeUnknown}
+			if assignment.Principal != nil {
+				principal = principalFromObject(*assignment.Principal)
+			}
+
+			scopeName, ok := scopeNames[assignment.DirectoryScopeID]
+			if !ok {
+				scopeName = assignment.DirectoryScopeID
+			}
+
+			assignments = append(assignments, models.DirectoryRoleAssignment{
+				RoleName:       roleNames[assignment.RoleDefinitionID],
+				RoleTemplateID: templateIDs[assignment.RoleDefinitionID],
+				Scope:          assignment.DirectoryScopeID,
+				ScopeName:      scopeName,
+				Principal:      principal,
+			})
+		}
+		return assignments, nil
+	}
+
+	fmt.Printf("[WARN] Role management request failed, falling back to directoryRoles: %v\n", err)
+
+	roles, err := listAllGraphPages[models.DirectoryRole](ctx, token, graphBaseURL+"/directoryRoles?$expand=members")
+	if err != nil {
+		return nil, err
+	}
+
+	var assignments []models.DirectoryRoleAssignment
+	for _, role := range roles {
+		for _, member := range role.Members {
+			assignments = append(assignments, models.DirectoryRoleAssignment{
+				RoleName:       role.DisplayName,
+				RoleTemplateID: role.RoleTemplateID,
+				Scope:          "/",
+				ScopeName:      scopeNames["/"],
+				Principal:      principalFromObject(member),
+			})
+		}
+	}
+	return assignments, nil
+}
+
+// reportTokenDirectoryRoles lists the directory roles carried in the token's wids claim
+func reportTokenDirectoryRoles(claims *auth.TokenClaims, roleNames map[string]string) {
+	fmt.Println("\n=== CURRENT TOKEN DIRECTORY ROLES ===")
+
+	// Member users carry the default User role template, which grants nothing notable
+	const defaultUserRole = "b79fbf4d-3ef9-4689-8143-76b194e85509"
+
+	var held []string
+	for _, wid := range claims.WIDs {
+		if wid == defaultUserRole {
+			continue
+		}
+		held = append(held, wid)
+	}
+
+	if len(held) == 0 {
+		fmt.Println("[INFO] The token carries no directory roles.")
+		return
+	}
+
+	for _, wid := range held {
+		name, ok := roleNames[wid]
+		if !ok {
+			name = wid
+		}
+
+		if severity, ok := privilegedDirectoryRoles[wid]; ok {
+			fmt.Println(models.Finding{
+				Severity: severity,
+				Resource: claims.Identity(),
+				Title:    "Current token holds privileged directory role " + name,
+				Evidence: "wids=" + strings.Join(claims.WIDs, ","),
+			})
+			continue
+		}
+
+		fmt.Printf("[INFO] Directory Role: %s\n", name)
+	}
+}
@@ -0,0 +1,65 @@
+package models
+
+type AutomationAccount struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Location string `json:"location"`
+}
+
+type Runbook struct {
+	Name       string            `json:"name"`
+	Properties RunbookProperties `json:"properties"`
+}
+
+type RunbookProperties struct {
+	RunbookType      string `json:"runbookType"`
+	State            string `json:"state"`
+	LastModifiedTime string `json:"lastModifiedTime"`
+}
+
+type AutomationVariable struct {
+	Name       string                       `json:"name"`
+	Properties AutomationVariableProperties `json:"properties"`
+}
+
+type AutomationVariableProperties struct {
+	IsEncrypted bool   `json:"isEncrypted"`
+	Value       string `json:"value"`
+	Description string `json:"description"`
+}
+
+type AutomationCredential struct {
+	Name       string `json:"name"`
+	Properties struct {
+		UserName    string `json:"userName"`
+		Description string `json:"description"`
+	} `json:"properties"`
+}
+
+type AutomationConnection struct {
+	Name       string `json:"name"`
+	Properties struct {
+		ConnectionType struct {
+			Name string `json:"name"`
+		} `json:"connectionType"`
+	} `json:"properties"`
+}
+
+type AutomationSchedule struct {
+	Name       string `json:"name"`
+	Properties struct {
+		Frequency string `json:"frequency"`
+		NextRun   string `json:"nextRun"`
+		IsEnabled bool   `json:"isEnabled"`
+	} `json:"properties"`
+}
+
+type HybridWorkerGroup struct {
+	Name       string `json:"name"`
+	Properties struct {
+		GroupType  string `json:"groupType"`
+		Credential *struct {
+			Name string `json:"name"`
+		} `json:"credential"`
+	} `json:"properties"`
+}
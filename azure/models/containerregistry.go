@@ -0,0 +1,35 @@
+package models
+
+type ContainerRegistry struct {
+	ID         string                      `json:"id"`
+	Name       string                      `json:"name"`
+	Location   string                      `json:"location"`
+	SKU        *SKU                        `json:"sku"`
+	Properties ContainerRegistryProperties `json:"properties"`
+}
+
+type ContainerRegistryProperties struct {
+	LoginServer          string `json:"loginServer"`
+	AdminUserEnabled     bool   `json:"adminUserEnabled"`
+	AnonymousPullEnabled bool   `json:"anonymousPullEnabled"`
+	PublicNetworkAccess  string `json:"publicNetworkAccess"`
+}
+
+type ContainerRegistryCredentials struct {
+	Username  string `json:"username"`
+	Passwords []struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	} `json:"passwords"`
+}
+
+// RegistryCatalog is the Docker Registry v2 /v2/_catalog response
+type RegistryCatalog struct {
+	Repositories []string `json:"repositories"`
+}
+
+// RegistryTags is the Docker Registry v2 /v2/<name>/tags/list response
+type RegistryTags struct {
+	Name string   `json:"name"`
+	Tags []string `json:"tags"`
+}
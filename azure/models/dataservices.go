@@ -0,0 +1,110 @@
+package models
+
+type SQLServer struct {
+	ID         string              `json:"id"`
+	Name       string              `json:"name"`
+	Location   string              `json:"location"`
+	Properties SQLServerProperties `json:"properties"`
+}
+
+type SQLServerProperties struct {
+	FullyQualifiedDomainName string `json:"fullyQualifiedDomainName"`
+	AdministratorLogin       string `json:"administratorLogin"`
+	PublicNetworkAccess      string `json:"publicNetworkAccess"`
+	MinimalTLSVersion        string `json:"minimalTlsVersion"`
+}
+
+type SQLFirewallRule struct {
+	Name       string `json:"name"`
+	Properties struct {
+		StartIPAddress string `json:"startIpAddress"`
+		EndIPAddress   string `json:"endIpAddress"`
+	} `json:"properties"`
+}
+
+type SQLAdministrator struct {
+	Name       string `json:"name"`
+	Properties struct {
+		AdministratorType string `json:"administratorType"`
+		Login             string `json:"login"`
+		SID               string `json:"sid"`
+	} `json:"properties"`
+}
+
+type SQLADOnlyAuthentication struct {
+	Properties struct {
+		AzureADOnlyAuthentication bool `json:"azureADOnlyAuthentication"`
+	} `json:"properties"`
+}
+
+type SQLAuditingSettings struct {
+	Properties struct {
+		State                       string `json:"state"`
+		IsAzureMonitorTargetEnabled bool   `json:"isAzureMonitorTargetEnabled"`
+		StorageEndpoint             string `json:"storageEndpoint"`
+		RetentionDays               int    `json:"retentionDays"`
+	} `json:"properties"`
+}
+
+type SQLDatabase struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	SKU        *SKU   `json:"sku"`
+	Properties struct {
+		Status string `json:"status"`
+	} `json:"properties"`
+}
+
+type CosmosDBAccount struct {
+	ID         string                    `json:"id"`
+	Name       string                    `json:"name"`
+	Location   string                    `json:"location"`
+	Kind       string                    `json:"kind"`
+	Properties CosmosDBAccountProperties `json:"properties"`
+}
+
+type CosmosDBAccountProperties struct {
+	DocumentEndpoint              string `json:"documentEndpoint"`
+	PublicNetworkAccess           string `json:"publicNetworkAccess"`
+	IsVirtualNetworkFilterEnabled bool   `json:"isVirtualNetworkFilterEnabled"`
+	DisableLocalAuth              bool   `json:"disableLocalAuth"`
+	IPRules                       []struct {
+		IPAddressOrRange string `json:"ipAddressOrRange"`
+	} `json:"ipRules"`
+}
+
+type CosmosDBKeys struct {
+	PrimaryMasterKey           string `json:"primaryMasterKey"`
+	SecondaryMasterKey         string `json:"secondaryMasterKey"`
+	PrimaryReadonlyMasterKey   string `json:"primaryReadonlyMasterKey"`
+	SecondaryReadonlyMasterKey string `json:"secondaryReadonlyMasterKey"`
+}
+
+type CosmosDBConnectionStrings struct {
+	ConnectionStrings []struct {
+		ConnectionString string `json:"connectionString"`
+		Description      string `json:"description"`
+	} `json:"connectionStrings"`
+}
+
+type RedisCache struct {
+	ID         string               `json:"id"`
+	Name       string               `json:"name"`
+	Location   string               `json:"location"`
+	Properties RedisCacheProperties `json:"properties"`
+}
+
+type RedisCacheProperties struct {
+	HostName                       string `json:"hostName"`
+	Port                           int    `json:"port"`
+	SSLPort                        int    `json:"sslPort"`
+	EnableNonSSLPort               bool   `json:"enableNonSslPort"`
+	PublicNetworkAccess            string `json:"publicNetworkAccess"`
+	MinimumTLSVersion              string `json:"minimumTlsVersion"`
+	DisableAccessKeyAuthentication bool   `json:"disableAccessKeyAuthentication"`
+}
+
+type RedisKeys struct {
+	PrimaryKey   string `json:"primaryKey"`
+	SecondaryKey string `json:"secondaryKey"`
+}
@@ -0,0 +1,38 @@
+package models
+
+import "fmt"
+
+const (
+	SeverityCritical = "CRITICAL"
+	SeverityHigh     = "HIGH"
+	SeverityMedium   = "MEDIUM"
+	SeverityLow      = "LOW"
+)
+
+// Finding is a single misconfiguration or exposure observed during enumeration
+type Finding struct {
+	Severity string `json:"severity"`
+	Resource string `json:"resource"`
+	Title    string `json:"title"`
+	Evidence string `json:"evidence"`
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("[%s] %s: %s (%s)", f.Severity, f.Resource, f.Title, f.Evidence)
+}
+
+// SeverityRank orders severities from least to most severe, returning 0 for unknown values
+func SeverityRank(severity string) int {
+	switch severity {
+	case SeverityLow:
+		return 1
+	case SeverityMedium:
+		return 2
+	case SeverityHigh:
+		return 3
+	case SeverityCritical:
+		return 4
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,280 @@
+package models
+
+import "fmt"
+
+const (
+	PrincipalTypeUser             = "User"
+	PrincipalTypeGroup            = "Group"
+	PrincipalTypeServicePrincipal = "ServicePrincipal"
+	PrincipalTypeManagedIdentity  = "ManagedIdentity"
+	PrincipalTypeUnknown          = "Unknown"
+)
+
+// DirectoryObject is the subset of Microsoft Graph directory object fields used to identify a principal
+type DirectoryObject struct {
+	ODataType            string `json:"@odata.type"`
+	ID                   string `json:"id"`
+	DisplayName          string `json:"displayName"`
+	UserPrincipalName    string `json:"userPrincipalName"`
+	AppID                string `json:"appId"`
+	ServicePrincipalType string `json:"servicePrincipalType"`
+}
+
+// Principal is a resolved directory identity that can hold role assignments
+type Principal struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"displayName"`
+	// UPN for users, appId for service principals and managed identities
+	Identifier string `json:"identifier"`
+	Type       string `json:"type"`
+}
+
+// Label renders a principal for output, falling back to the bare ID when it is unresolved
+func (p Principal) Label() string {
+	if p.DisplayName == "" {
+		return p.ID
+	}
+	if p.Identifier == "" {
+		return p.DisplayName
+	}
+	return fmt.Sprintf("%s <%s>", p.DisplayName, p.Identifier)
+}
+
+type GraphUser struct {
+	ID                string               `json:"id"`
+	DisplayName       string               `json:"displayName"`
+	UserPrincipalName string               `json:"userPrincipalName"`
+	Mail              string               `json:"mail"`
+	UserType          string               `json:"userType"`
+	AccountEnabled    bool                 `json:"accountEnabled"`
+	CreatedDateTime   string               `json:"createdDateTime"`
+	SignInActivity    *GraphSignInActivity `json:"signInActivity,omitempty"`
+}
+
+type GraphSignInActivity struct {
+	LastSignInDateTime               string `json:"lastSignInDateTime"`
+	LastNonInteractiveSignInDateTime string `json:"lastNonInteractiveSignInDateTime"`
+}
+
+type GraphGroup struct {
+	ID                            string   `json:"id"`
+	DisplayName                   string   `json:"displayName"`
+	Description                   string   `json:"description"`
+	GroupTypes                    []string `json:"groupTypes"`
+	SecurityEnabled               bool     `json:"securityEnabled"`
+	MailEnabled                   bool     `json:"mailEnabled"`
+	IsAssignableToRole            bool     `json:"isAssignableToRole"`
+	MembershipRule                string   `json:"membershipRule"`
+	MembershipRuleProcessingState string   `json:"membershipRuleProcessingState"`
+	// Members is only collected with `graph groups --members`
+	Members []Principal `json:"members,omitempty"`
+}
+
+type GraphServicePrincipal struct {
+	ID                     string               `json:"id"`
+	AppID                  string               `json:"appId"`
+	DisplayName            string               `json:"displayName"`
+	ServicePrincipalType   string               `json:"servicePrincipalType"`
+	AccountEnabled         bool                 `json:"accountEnabled"`
+	AppOwnerOrganizationID string               `json:"appOwnerOrganizationId"`
+	PasswordCredentials    []PasswordCredential `json:"passwordCredentials,omitempty"`
+	KeyCredentials         []KeyCredential      `json:"keyCredentials,omitempty"`
+	AppRoles               []AppRole            `json:"appRoles,omitempty"`
+	PublisherName          string               `json:"publisherName,omitempty"`
+	VerifiedPublisher      *VerifiedPublisher   `json:"verifiedPublisher,omitempty"`
+}
+
+type VerifiedPublisher struct {
+	DisplayName string `json:"displayName"`
+}
+
+type GraphApplication struct {
+	ID                  string               `json:"id"`
+	AppID               string               `json:"appId"`
+	DisplayName         string               `json:"displayName"`
+	SignInAudience      string               `json:"signInAudience"`
+	CreatedDateTime     string               `json:"createdDateTime"`
+	PasswordCredentials []PasswordCredential `json:"passwordCredentials,omitempty"`
+	KeyCredentials      []KeyCredential      `json:"keyCredentials,omitempty"`
+}
+
+// PasswordCredential is a client secret; Graph only returns the first characters as the hint
+type PasswordCredential struct {
+	KeyID         string `json:"keyId"`
+	DisplayName   string `json:"displayName"`
+	Hint          string `json:"hint"`
+	StartDateTime string `json:"startDateTime"`
+	EndDateTime   string `json:"endDateTime"`
+}
+
+// KeyCredential is a certificate or public key registered on an application or service principal
+type KeyCredential struct {
+	KeyID         string `json:"keyId"`
+	DisplayName   string `json:"displayName"`
+	Type          string `json:"type"`
+	Usage         string `json:"usage"`
+	StartDateTime string `json:"startDateTime"`
+	EndDateTime   string `json:"endDateTime"`
+}
+
+type AppRole struct {
+	ID                 string   `json:"id"`
+	Value              string   `json:"value"`
+	DisplayName        string   `json:"displayName"`
+	AllowedMemberTypes []string `json:"allowedMemberTypes"`
+}
+
+type AppRoleAssignment struct {
+	ID                   string `json:"id"`
+	AppRoleID            string `json:"appRoleId"`
+	PrincipalID          string `json:"principalId"`
+	PrincipalDisplayName string `json:"principalDisplayName"`
+	PrincipalType        string `json:"principalType"`
+	ResourceID           string `json:"resourceId"`
+	ResourceDisplayName  string `json:"resourceDisplayName"`
+	CreatedDateTime      string `json:"createdDateTime"`
+}
+
+// OAuth2PermissionGrant is a delegated permission consent. ConsentType AllPrincipals
+// is a tenant-wide admin consent, Principal is a consent for the single user PrincipalID.
+type OAuth2PermissionGrant struct {
+	ID          string `json:"id"`
+	ClientID    string `json:"clientId"`
+	ConsentType string `json:"consentType"`
+	PrincipalID string `json:"principalId"`
+	ResourceID  string `json:"resourceId"`
+	Scope       string `json:"scope"`
+}
+
+// AppConsent summarises the delegated and application permissions consented to a client app
+type AppConsent struct {
+	ObjectID    string `json:"objectId"`
+	AppID       string `json:"appId"`
+	DisplayName string `json:"displayName"`
+	Publisher   string `json:"publisher"`
+	External    bool   `json:"external"`
+	// AdminConsented holds "Resource/scope" delegated permissions consented for all users
+	AdminConsented []string `json:"adminConsented,omitempty"`
+	// UserConsented maps "Resource/scope" delegated permissions to the users who consented
+	UserConsented map[string][]string `json:"userConsented,omitempty"`
+	// ApplicationPermissions holds "Resource/role" application permissions
+	ApplicationPermissions []string `json:"applicationPermissions,omitempty"`
+}
+
+// AppAudit kinds tell app registrations apart from service principals
+const (
+	AppAuditKindApplication      = "Application"
+	AppAuditKindServicePrincipal = "ServicePrincipal"
+)
+
+// AppAudit summarises the credentials, owners and application permissions of an
+// app registration or service principal
+type AppAudit struct {
+	ObjectID    string `json:"objectId"`
+	AppID       string `json:"appId"`
+	DisplayName string `json:"displayName"`
+	// Kind is AppAuditKindApplication or AppAuditKindServicePrincipal
+	Kind string `json:"kind"`
+	// ServicePrincipalType is Graph's servicePrincipalType, such as Application or
+	// ManagedIdentity, and is empty for app registrations
+	ServicePrincipalType string               `json:"servicePrincipalType,omitempty"`
+	Owners               []Principal          `json:"owners"`
+	PasswordCredentials  []PasswordCredential `json:"passwordCredentials"`
+	KeyCredentials       []KeyCredential      `json:"keyCredentials"`
+	// AppPermissions are granted application permissions as "Resource/Permission"
+	AppPermissions []string `json:"appPermissions"`
+}
+
+type DirectoryRoleDefinition struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"displayName"`
+	TemplateID  string `json:"templateId"`
+	IsBuiltIn   bool   `json:"isBuiltIn"`
+}
+
+// UnifiedRoleAssignment is a directory role assignment from roleManagement/directory
+type UnifiedRoleAssignment struct {
+	ID               string           `json:"id"`
+	RoleDefinitionID string           `json:"roleDefinitionId"`
+	PrincipalID      string           `json:"principalId"`
+	DirectoryScopeID string           `json:"directoryScopeId"`
+	Principal        *DirectoryObject `json:"principal"`
+}
+
+// DirectoryRole is an activated directory role from the legacy directoryRoles collection
+type DirectoryRole struct {
+	ID             string            `json:"id"`
+	DisplayName    string            `json:"displayName"`
+	RoleTemplateID string            `json:"roleTemplateId"`
+	Members        []DirectoryObject `json:"members"`
+}
+
+type AdministrativeUnit struct {
+	ID                           string `json:"id"`
+	DisplayName                  string `json:"displayName"`
+	IsMemberManagementRestricted bool   `json:"isMemberManagementRestricted"`
+}
+
+// DirectoryRoleAssignment is a resolved directory role membership saved to the session
+type DirectoryRoleAssignment struct {
+	RoleName       string    `json:"roleName"`
+	RoleTemplateID string    `json:"roleTemplateId"`
+	Scope          string    `json:"scope"`
+	ScopeName      string    `json:"scopeName"`
+	Principal      Principal `json:"principal"`
+}
+
+type ConditionalAccessPolicy struct {
+	ID            string                      `json:"id"`
+	DisplayName   string                      `json:"displayName"`
+	State         string                      `json:"state"`
+	Conditions    ConditionalAccessConditions `json:"conditions"`
+	GrantControls *ConditionalAccessGrant     `json:"grantControls"`
+}
+
+type ConditionalAccessConditions struct {
+	ClientAppTypes []string `json:"clientAppTypes"`
+	Users          *struct {
+		IncludeUsers  []string `json:"includeUsers"`
+		ExcludeUsers  []string `json:"excludeUsers"`
+		IncludeGroups []string `json:"includeGroups"`
+		ExcludeGroups []string `json:"excludeGroups"`
+		IncludeRoles  []string `json:"includeRoles"`
+		ExcludeRoles  []string `json:"excludeRoles"`
+	} `json:"users"`
+	Applications *struct {
+		IncludeApplications []string `json:"includeApplications"`
+		ExcludeApplications []string `json:"excludeApplications"`
+	} `json:"applications"`
+	Locations *struct {
+		IncludeLocations []string `json:"includeLocations"`
+		ExcludeLocations []string `json:"excludeLocations"`
+	} `json:"locations"`
+}
+
+type ConditionalAccessGrant struct {
+	Operator               string   `json:"operator"`
+	BuiltInControls        []string `json:"builtInControls"`
+	AuthenticationStrength *struct {
+		DisplayName string `json:"displayName"`
+	} `json:"authenticationStrength"`
+}
+
+// NamedLocation covers both IP and country named locations, distinguished by ODataType
+type NamedLocation struct {
+	ODataType                         string   `json:"@odata.type"`
+	ID                                string   `json:"id"`
+	DisplayName                       string   `json:"displayName"`
+	IsTrusted                         bool     `json:"isTrusted"`
+	CountriesAndRegions               []string `json:"countriesAndRegions"`
+	IncludeUnknownCountriesAndRegions bool     `json:"includeUnknownCountriesAndRegions"`
+	IPRanges                          []struct {
+		CIDRAddress string `json:"cidrAddress"`
+	} `json:"ipRanges"`
+}
+
+// ConditionalAccessSnapshot is what `graph conditional-access` saves to the session
+type ConditionalAccessSnapshot struct {
+	Policies       []ConditionalAccessPolicy `json:"policies"`
+	NamedLocations []NamedLocation           `json:"namedLocations"`
+}
@@ -0,0 +1,57 @@
+package models
+
+import "strings"
+
+// ManagedServiceIdentity is the identity block of a resource that supports managed identities
+type ManagedServiceIdentity struct {
+	Type                   string                          `json:"type"`
+	PrincipalID            string                          `json:"principalId"`
+	TenantID               string                          `json:"tenantId"`
+	UserAssignedIdentities map[string]UserAssignedIdentity `json:"userAssignedIdentities"`
+}
+
+type UserAssignedIdentity struct {
+	PrincipalID string `json:"principalId"`
+	ClientID    string `json:"clientId"`
+}
+
+// IdentityResource is any ARM resource listed for its managed identities
+type IdentityResource struct {
+	ID       string                  `json:"id"`
+	Name     string                  `json:"name"`
+	Type     string                  `json:"type"`
+	Kind     string                  `json:"kind"`
+	Location string                  `json:"location"`
+	Identity *ManagedServiceIdentity `json:"identity"`
+}
+
+// UserAssignedIdentityResource is a Microsoft.ManagedIdentity/userAssignedIdentities resource
+type UserAssignedIdentityResource struct {
+	ID         string               `json:"id"`
+	Name       string               `json:"name"`
+	Location   string               `json:"location"`
+	Properties UserAssignedIdentity `json:"properties"`
+}
+
+// IdentityAttachment records a managed identity attached to a compute resource, saved to
+// the session for attack path analysis
+type IdentityAttachment struct {
+	ResourceID   string `json:"resourceId"`
+	ResourceName string `json:"resourceName"`
+	ResourceType string `json:"resourceType"`
+	// IdentityType is SystemAssigned or UserAssigned
+	IdentityType string `json:"identityType"`
+	// IdentityID is the resource ID of a user-assigned identity
+	IdentityID  string `json:"identityId,omitempty"`
+	PrincipalID string `json:"principalId"`
+	ClientID    string `json:"clientId,omitempty"`
+}
+
+// Name returns the display name of the identity: the user-assigned identity's resource
+// name, or the name of the resource a system-assigned identity belongs to
+func (a IdentityAttachment) Name() string {
+	if a.IdentityID != "" {
+		return a.IdentityID[strings.LastIndex(a.IdentityID, "/")+1:]
+	}
+	return a.ResourceName
+}
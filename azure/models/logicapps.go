@@ -0,0 +1,65 @@
+package models
+
+type LogicAppWorkflow struct {
+	ID         string                     `json:"id"`
+	Name       string                     `json:"name"`
+	Location   string                     `json:"location"`
+	Properties LogicAppWorkflowProperties `json:"properties"`
+}
+
+type LogicAppWorkflowProperties struct {
+	State          string                 `json:"state"`
+	AccessEndpoint string                 `json:"accessEndpoint"`
+	Definition     map[string]interface{} `json:"definition"`
+	Parameters     map[string]interface{} `json:"parameters"`
+}
+
+type LogicAppRun struct {
+	Name       string `json:"name"`
+	Properties struct {
+		Status    string `json:"status"`
+		StartTime string `json:"startTime"`
+		Trigger   struct {
+			Name        string       `json:"name"`
+			InputsLink  *ContentLink `json:"inputsLink"`
+			OutputsLink *ContentLink `json:"outputsLink"`
+		} `json:"trigger"`
+	} `json:"properties"`
+}
+
+// ContentLink points at run inputs or outputs through a pre-signed URL
+type ContentLink struct {
+	URI string `json:"uri"`
+}
+
+// APIConnection is a Microsoft.Web/connections resource used by Logic Apps connectors
+type APIConnection struct {
+	ID         string                  `json:"id"`
+	Name       string                  `json:"name"`
+	Location   string                  `json:"location"`
+	Properties APIConnectionProperties `json:"properties"`
+}
+
+type APIConnectionProperties struct {
+	DisplayName string `json:"displayName"`
+	API         struct {
+		Name        string `json:"name"`
+		DisplayName string `json:"displayName"`
+	} `json:"api"`
+	ParameterValues          map[string]interface{} `json:"parameterValues"`
+	NonSecretParameterValues map[string]interface{} `json:"nonSecretParameterValues"`
+	Statuses                 []struct {
+		Status string `json:"status"`
+	} `json:"statuses"`
+	AuthenticatedUser struct {
+		Name string `json:"name"`
+	} `json:"authenticatedUser"`
+}
+
+// APIConnectionKeys is returned by listConnectionKeys. The key authenticates calls to the
+// connection's runtime URL, which act with the connection's stored credentials.
+type APIConnectionKeys struct {
+	ConnectionKey   string                 `json:"connectionKey"`
+	RuntimeURLs     []string               `json:"runtimeUrls"`
+	ParameterValues map[string]interface{} `json:"parameterValues"`
+}
@@ -1,5 +1,7 @@
 package models
 
+import "strings"
+
 type RoleDefinitionsResponse struct {
 	Value []RoleDefinition `json:"value"`
 }
@@ -10,6 +12,12 @@ type RoleDefinition struct {
 	Properties RoleDefinitionProperties `json:"properties"`
 }
 
+// RoleDefinitionKey returns the GUID of a role definition ID, which is shared by every
+// scope the definition is referenced from
+func RoleDefinitionKey(id string) string {
+	return strings.ToLower(id[strings.LastIndex(id, "/")+1:])
+}
+
 type RoleDefinitionProperties struct {
 	RoleName         string       `json:"roleName"`
 	Description      string       `json:"description"`
@@ -19,9 +27,14 @@ type RoleDefinitionProperties struct {
 }
 
 type Permission struct {
-	Actions     []string `json:"actions"`
-	NotActions  []string `json:"notActions"`
-	DataActions []string `json:"dataActions"`
+	Actions        []string `json:"actions"`
+	NotActions     []string `json:"notActions"`
+	DataActions    []string `json:"dataActions"`
+	NotDataActions []string `json:"notDataActions"`
+}
+
+type PermissionsResponse struct {
+	Value []Permission `json:"value"`
 }
 
 type RoleAssignmentsResponse struct {
@@ -35,6 +48,70 @@ type RoleAssignment struct {
 
 type RoleAssignmentProperties struct {
 	PrincipalID      string `json:"principalId"`
+	PrincipalType    string `json:"principalType"`
+	RoleDefinitionID string `json:"roleDefinitionId"`
+	Scope            string `json:"scope"`
+	Description      string `json:"description"`
+	// Condition is an ABAC expression limiting the assignment, e.g. to specific blob containers
+	Condition        string `json:"condition"`
+	ConditionVersion string `json:"conditionVersion"`
+	CreatedOn        string `json:"createdOn"`
+	UpdatedOn        string `json:"updatedOn"`
+	CreatedBy        string `json:"createdBy"`
+}
+
+// ResolvedRoleAssignment is a role assignment with its role and principal resolved, as
+// saved to the session for attack path analysis and exports
+type ResolvedRoleAssignment struct {
+	ID               string    `json:"id"`
+	Scope            string    `json:"scope"`
+	RoleDefinitionID string    `json:"roleDefinitionId"`
+	RoleName         string    `json:"roleName"`
+	Principal        Principal `json:"principal"`
+	Condition        string    `json:"condition,omitempty"`
+}
+
+type ManagementGroup struct {
+	ID         string                    `json:"id"`
+	Name       string                    `json:"name"`
+	Type       string                    `json:"type"`
+	Properties ManagementGroupProperties `json:"properties"`
+}
+
+type ManagementGroupProperties struct {
+	DisplayName string                 `json:"displayName"`
+	TenantID    string                 `json:"tenantId"`
+	Children    []ManagementGroupChild `json:"children"`
+}
+
+// ManagementGroupChild is a node of the expanded management group hierarchy, either a
+// nested management group or a subscription
+type ManagementGroupChild struct {
+	ID          string                 `json:"id"`
+	Name        string                 `json:"name"`
+	Type        string                 `json:"type"`
+	DisplayName string                 `json:"displayName"`
+	Children    []ManagementGroupChild `json:"children"`
+}
+
+// RoleScheduleInstance is a PIM eligibility or active assignment schedule instance
+type RoleScheduleInstance struct {
+	ID         string                         `json:"id"`
+	Name       string                         `json:"name"`
+	Properties RoleScheduleInstanceProperties `json:"properties"`
+}
+
+type RoleScheduleInstanceProperties struct {
+	PrincipalID      string `json:"principalId"`
+	PrincipalType    string `json:"principalType"`
 	RoleDefinitionID string `json:"roleDefinitionId"`
 	Scope            string `json:"scope"`
+	StartDateTime    string `json:"startDateTime"`
+	EndDateTime      string `json:"endDateTime"`
+	// MemberType is Direct, Group or Inherited
+	MemberType string `json:"memberType"`
+	Status     string `json:"status"`
+	// AssignmentType is Assigned or Activated, only set on active assignment instances
+	AssignmentType string `json:"assignmentType"`
+	Condition      string `json:"condition"`
 }
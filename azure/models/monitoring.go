@@ -0,0 +1,57 @@
+package models
+
+// DefenderPricing is the Defender for Cloud plan of a subscription for one resource type
+type DefenderPricing struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Properties struct {
+		// PricingTier is Free or Standard, Standard means the plan is enabled
+		PricingTier string `json:"pricingTier"`
+		SubPlan     string `json:"subPlan"`
+	} `json:"properties"`
+}
+
+type SecurityContact struct {
+	Name       string `json:"name"`
+	Properties struct {
+		Emails             string `json:"emails"`
+		Phone              string `json:"phone"`
+		AlertNotifications struct {
+			State           string `json:"state"`
+			MinimalSeverity string `json:"minimalSeverity"`
+		} `json:"alertNotifications"`
+		NotificationsByRole struct {
+			State string   `json:"state"`
+			Roles []string `json:"roles"`
+		} `json:"notificationsByRole"`
+	} `json:"properties"`
+}
+
+// DiagnosticSetting routes resource or activity logs to a workspace, storage account,
+// event hub or partner solution
+type DiagnosticSetting struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Properties struct {
+		WorkspaceID                 string `json:"workspaceId"`
+		StorageAccountID            string `json:"storageAccountId"`
+		EventHubAuthorizationRuleID string `json:"eventHubAuthorizationRuleId"`
+		EventHubName                string `json:"eventHubName"`
+		MarketplacePartnerID        string `json:"marketplacePartnerId"`
+		Logs                        []struct {
+			Category      string `json:"category"`
+			CategoryGroup string `json:"categoryGroup"`
+			Enabled       bool   `json:"enabled"`
+		} `json:"logs"`
+	} `json:"properties"`
+}
+
+// LogProfile is the legacy activity log export of a subscription
+type LogProfile struct {
+	Name       string `json:"name"`
+	Properties struct {
+		StorageAccountID string   `json:"storageAccountId"`
+		ServiceBusRuleID string   `json:"serviceBusRuleId"`
+		Categories       []string `json:"categories"`
+	} `json:"properties"`
+}
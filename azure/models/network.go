@@ -0,0 +1,256 @@
+package models
+
+// SubResource is a reference to another ARM resource or child resource
+type SubResource struct {
+	ID string `json:"id"`
+}
+
+type NetworkSecurityGroup struct {
+	ID         string                         `json:"id"`
+	Name       string                         `json:"name"`
+	Location   string                         `json:"location"`
+	Properties NetworkSecurityGroupProperties `json:"properties"`
+}
+
+type NetworkSecurityGroupProperties struct {
+	SecurityRules        []SecurityRule `json:"securityRules"`
+	DefaultSecurityRules []SecurityRule `json:"defaultSecurityRules"`
+	NetworkInterfaces    []SubResource  `json:"networkInterfaces"`
+	Subnets              []SubResource  `json:"subnets"`
+}
+
+type SecurityRule struct {
+	Name       string                 `json:"name"`
+	Properties SecurityRuleProperties `json:"properties"`
+}
+
+type SecurityRuleProperties struct {
+	Protocol                   string   `json:"protocol"`
+	SourceAddressPrefix        string   `json:"sourceAddressPrefix"`
+	SourceAddressPrefixes      []string `json:"sourceAddressPrefixes"`
+	DestinationAddressPrefix   string   `json:"destinationAddressPrefix"`
+	DestinationAddressPrefixes []string `json:"destinationAddressPrefixes"`
+	DestinationPortRange       string   `json:"destinationPortRange"`
+	DestinationPortRanges      []string `json:"destinationPortRanges"`
+	Access                     string   `json:"access"`
+	Priority                   int      `json:"priority"`
+	Direction                  string   `json:"direction"`
+}
+
+type PublicIPAddress struct {
+	ID         string                    `json:"id"`
+	Name       string                    `json:"name"`
+	Location   string                    `json:"location"`
+	SKU        *SKU                      `json:"sku"`
+	Properties PublicIPAddressProperties `json:"properties"`
+}
+
+type PublicIPAddressProperties struct {
+	IPAddress                string       `json:"ipAddress"`
+	PublicIPAllocationMethod string       `json:"publicIPAllocationMethod"`
+	DNSSettings              *PublicIPDNS `json:"dnsSettings"`
+	IPConfiguration          *SubResource `json:"ipConfiguration"`
+}
+
+type PublicIPDNS struct {
+	FQDN string `json:"fqdn"`
+}
+
+type NetworkInterface struct {
+	ID         string                     `json:"id"`
+	Name       string                     `json:"name"`
+	Properties NetworkInterfaceProperties `json:"properties"`
+}
+
+type NetworkInterfaceProperties struct {
+	VirtualMachine       *SubResource             `json:"virtualMachine"`
+	NetworkSecurityGroup *SubResource             `json:"networkSecurityGroup"`
+	IPConfigurations     []NetworkIPConfiguration `json:"ipConfigurations"`
+}
+
+// NetworkIPConfiguration is an IP configuration of a NIC or a frontend of a load balancer,
+// application gateway or firewall
+type NetworkIPConfiguration struct {
+	ID         string                           `json:"id"`
+	Name       string                           `json:"name"`
+	Properties NetworkIPConfigurationProperties `json:"properties"`
+}
+
+type NetworkIPConfigurationProperties struct {
+	PrivateIPAddress string       `json:"privateIPAddress"`
+	PublicIPAddress  *SubResource `json:"publicIPAddress"`
+	Subnet           *SubResource `json:"subnet"`
+}
+
+type LoadBalancer struct {
+	ID         string                 `json:"id"`
+	Name       string                 `json:"name"`
+	SKU        *SKU                   `json:"sku"`
+	Properties LoadBalancerProperties `json:"properties"`
+}
+
+type LoadBalancerProperties struct {
+	FrontendIPConfigurations []NetworkIPConfiguration `json:"frontendIPConfigurations"`
+	BackendAddressPools      []BackendAddressPool     `json:"backendAddressPools"`
+	LoadBalancingRules       []LoadBalancerRule       `json:"loadBalancingRules"`
+	InboundNatRules          []LoadBalancerRule       `json:"inboundNatRules"`
+}
+
+type BackendAddressPool struct {
+	ID         string                       `json:"id"`
+	Name       string                       `json:"name"`
+	Properties BackendAddressPoolProperties `json:"properties"`
+}
+
+type BackendAddressPoolProperties struct {
+	BackendIPConfigurations []SubResource `json:"backendIPConfigurations"`
+}
+
+// LoadBalancerRule is a load balancing rule or inbound NAT rule
+type LoadBalancerRule struct {
+	ID         string                     `json:"id"`
+	Name       string                     `json:"name"`
+	Properties LoadBalancerRuleProperties `json:"properties"`
+}
+
+type LoadBalancerRuleProperties struct {
+	Protocol                string       `json:"protocol"`
+	FrontendPort            int          `json:"frontendPort"`
+	BackendPort             int          `json:"backendPort"`
+	FrontendIPConfiguration *SubResource `json:"frontendIPConfiguration"`
+	BackendAddressPool      *SubResource `json:"backendAddressPool"`
+	BackendIPConfiguration  *SubResource `json:"backendIPConfiguration"`
+}
+
+type ApplicationGateway struct {
+	ID         string                       `json:"id"`
+	Name       string                       `json:"name"`
+	Properties ApplicationGatewayProperties `json:"properties"`
+}
+
+type ApplicationGatewayProperties struct {
+	SKU                                 *SKU                         `json:"sku"`
+	FrontendIPConfigurations            []NetworkIPConfiguration     `json:"frontendIPConfigurations"`
+	FrontendPorts                       []ApplicationGatewayPort     `json:"frontendPorts"`
+	HTTPListeners                       []ApplicationGatewayListener `json:"httpListeners"`
+	WebApplicationFirewallConfiguration *ApplicationGatewayWAFConfig `json:"webApplicationFirewallConfiguration"`
+	FirewallPolicy                      *SubResource                 `json:"firewallPolicy"`
+}
+
+type ApplicationGatewayPort struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Properties struct {
+		Port int `json:"port"`
+	} `json:"properties"`
+}
+
+type ApplicationGatewayListener struct {
+	Name       string                               `json:"name"`
+	Properties ApplicationGatewayListenerProperties `json:"properties"`
+}
+
+type ApplicationGatewayListenerProperties struct {
+	Protocol                string       `json:"protocol"`
+	HostName                string       `json:"hostName"`
+	HostNames               []string     `json:"hostNames"`
+	FrontendIPConfiguration *SubResource `json:"frontendIPConfiguration"`
+	FrontendPort            *SubResource `json:"frontendPort"`
+}
+
+type ApplicationGatewayWAFConfig struct {
+	Enabled      bool   `json:"enabled"`
+	FirewallMode string `json:"firewallMode"`
+}
+
+// AzureFirewall holds classic rule collections. Firewalls managed by a firewall policy
+// keep their rules in the policy's rule collection groups instead.
+type AzureFirewall struct {
+	ID         string                  `json:"id"`
+	Name       string                  `json:"name"`
+	Properties AzureFirewallProperties `json:"properties"`
+}
+
+type AzureFirewallProperties struct {
+	IPConfigurations           []NetworkIPConfiguration        `json:"ipConfigurations"`
+	NetworkRuleCollections     []FirewallNetworkCollection     `json:"networkRuleCollections"`
+	NatRuleCollections         []FirewallNetworkCollection     `json:"natRuleCollections"`
+	ApplicationRuleCollections []FirewallApplicationCollection `json:"applicationRuleCollections"`
+	FirewallPolicy             *SubResource                    `json:"firewallPolicy"`
+}
+
+type FirewallAction struct {
+	Type string `json:"type"`
+}
+
+type FirewallNetworkCollection struct {
+	Name       string `json:"name"`
+	Properties struct {
+		Priority int                   `json:"priority"`
+		Action   FirewallAction        `json:"action"`
+		Rules    []FirewallNetworkRule `json:"rules"`
+	} `json:"properties"`
+}
+
+// FirewallNetworkRule is a classic network or DNAT rule
+type FirewallNetworkRule struct {
+	Name                 string   `json:"name"`
+	Protocols            []string `json:"protocols"`
+	SourceAddresses      []string `json:"sourceAddresses"`
+	DestinationAddresses []string `json:"destinationAddresses"`
+	DestinationPorts     []string `json:"destinationPorts"`
+	TranslatedAddress    string   `json:"translatedAddress"`
+	TranslatedPort       string   `json:"translatedPort"`
+}
+
+type FirewallApplicationCollection struct {
+	Name       string `json:"name"`
+	Properties struct {
+		Priority int                       `json:"priority"`
+		Action   FirewallAction            `json:"action"`
+		Rules    []FirewallApplicationRule `json:"rules"`
+	} `json:"properties"`
+}
+
+type FirewallApplicationRule struct {
+	Name            string             `json:"name"`
+	SourceAddresses []string           `json:"sourceAddresses"`
+	TargetFQDNs     []string           `json:"targetFqdns"`
+	Protocols       []FirewallProtocol `json:"protocols"`
+}
+
+type FirewallProtocol struct {
+	ProtocolType string `json:"protocolType"`
+	Port         int    `json:"port"`
+}
+
+type FirewallPolicyRuleCollectionGroup struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Properties struct {
+		Priority        int                            `json:"priority"`
+		RuleCollections []FirewallPolicyRuleCollection `json:"ruleCollections"`
+	} `json:"properties"`
+}
+
+type FirewallPolicyRuleCollection struct {
+	Name               string               `json:"name"`
+	RuleCollectionType string               `json:"ruleCollectionType"`
+	Priority           int                  `json:"priority"`
+	Action             FirewallAction       `json:"action"`
+	Rules              []FirewallPolicyRule `json:"rules"`
+}
+
+// FirewallPolicyRule is a network, NAT or application rule of a firewall policy
+type FirewallPolicyRule struct {
+	Name                 string             `json:"name"`
+	RuleType             string             `json:"ruleType"`
+	IPProtocols          []string           `json:"ipProtocols"`
+	Protocols            []FirewallProtocol `json:"protocols"`
+	SourceAddresses      []string           `json:"sourceAddresses"`
+	DestinationAddresses []string           `json:"destinationAddresses"`
+	DestinationPorts     []string           `json:"destinationPorts"`
+	TargetFQDNs          []string           `json:"targetFqdns"`
+	TranslatedAddress    string             `json:"translatedAddress"`
+	TranslatedPort       string             `json:"translatedPort"`
+}
@@ -0,0 +1,102 @@
+package models
+
+type PolicyAssignment struct {
+	ID         string                     `json:"id"`
+	Name       string                     `json:"name"`
+	Properties PolicyAssignmentProperties `json:"properties"`
+}
+
+type PolicyAssignmentProperties struct {
+	DisplayName        string                          `json:"displayName"`
+	PolicyDefinitionID string                          `json:"policyDefinitionId"`
+	Scope              string                          `json:"scope"`
+	NotScopes          []string                        `json:"notScopes"`
+	Parameters         map[string]PolicyParameterValue `json:"parameters"`
+	// EnforcementMode is Default or DoNotEnforce
+	EnforcementMode string `json:"enforcementMode"`
+}
+
+type PolicyParameterValue struct {
+	Value interface{} `json:"value"`
+}
+
+type PolicyParameterDefinition struct {
+	Type          string        `json:"type"`
+	DefaultValue  interface{}   `json:"defaultValue"`
+	AllowedValues []interface{} `json:"allowedValues"`
+}
+
+type PolicyDefinition struct {
+	ID         string                     `json:"id"`
+	Name       string                     `json:"name"`
+	Properties PolicyDefinitionProperties `json:"properties"`
+}
+
+type PolicyDefinitionProperties struct {
+	DisplayName string                               `json:"displayName"`
+	PolicyType  string                               `json:"policyType"`
+	Mode        string                               `json:"mode"`
+	Parameters  map[string]PolicyParameterDefinition `json:"parameters"`
+	PolicyRule  struct {
+		Then struct {
+			Effect interface{} `json:"effect"`
+		} `json:"then"`
+	} `json:"policyRule"`
+}
+
+// PolicySetDefinition is a policy initiative
+type PolicySetDefinition struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Properties struct {
+		DisplayName       string                               `json:"displayName"`
+		PolicyType        string                               `json:"policyType"`
+		Parameters        map[string]PolicyParameterDefinition `json:"parameters"`
+		PolicyDefinitions []PolicySetMember                    `json:"policyDefinitions"`
+	} `json:"properties"`
+}
+
+type PolicySetMember struct {
+	PolicyDefinitionID          string                          `json:"policyDefinitionId"`
+	PolicyDefinitionReferenceID string                          `json:"policyDefinitionReferenceId"`
+	Parameters                  map[string]PolicyParameterValue `json:"parameters"`
+}
+
+type PolicyExemption struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Properties struct {
+		DisplayName                  string   `json:"displayName"`
+		PolicyAssignmentID           string   `json:"policyAssignmentId"`
+		PolicyDefinitionReferenceIDs []string `json:"policyDefinitionReferenceIds"`
+		// ExemptionCategory is Waiver or Mitigated
+		ExemptionCategory string `json:"exemptionCategory"`
+		ExpiresOn         string `json:"expiresOn"`
+	} `json:"properties"`
+}
+
+// PolicyStatesSummary is the PolicyInsights summarize response for a scope
+type PolicyStatesSummary struct {
+	Value []struct {
+		Results           PolicySummaryResults `json:"results"`
+		PolicyAssignments []struct {
+			PolicyAssignmentID string               `json:"policyAssignmentId"`
+			Results            PolicySummaryResults `json:"results"`
+		} `json:"policyAssignments"`
+	} `json:"value"`
+}
+
+type PolicySummaryResults struct {
+	NonCompliantResources int `json:"nonCompliantResources"`
+	NonCompliantPolicies  int `json:"nonCompliantPolicies"`
+}
+
+// PolicyState is the latest compliance state of a resource against one policy
+type PolicyState struct {
+	ResourceID             string `json:"resourceId"`
+	PolicyAssignmentID     string `json:"policyAssignmentId"`
+	PolicyAssignmentName   string `json:"policyAssignmentName"`
+	PolicyDefinitionName   string `json:"policyDefinitionName"`
+	PolicyDefinitionAction string `json:"policyDefinitionAction"`
+	ComplianceState        string `json:"complianceState"`
+}
@@ -0,0 +1,76 @@
+package models
+
+type ResourceGroup struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Location string `json:"location"`
+}
+
+// Resource is an ARM resource recorded in the session for attack path analysis and exports
+type Resource struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Location string `json:"location"`
+}
+
+// SKU is the pricing tier of a resource
+type SKU struct {
+	Name string `json:"name"`
+	Tier string `json:"tier"`
+}
+
+type Deployment struct {
+	ID         string               `json:"id"`
+	Name       string               `json:"name"`
+	Properties DeploymentProperties `json:"properties"`
+}
+
+type DeploymentProperties struct {
+	ProvisioningState string                 `json:"provisioningState"`
+	Timestamp         string                 `json:"timestamp"`
+	Parameters        map[string]interface{} `json:"parameters"`
+	Outputs           map[string]interface{} `json:"outputs"`
+}
+
+type DeploymentExport struct {
+	Template map[string]interface{} `json:"template"`
+}
+
+type Subscription struct {
+	ID             string `json:"id"`
+	SubscriptionID string `json:"subscriptionId"`
+	DisplayName    string `json:"displayName"`
+	State          string `json:"state"`
+}
+
+// ResourceGraphRequest is the body of a Microsoft.ResourceGraph/resources query
+type ResourceGraphRequest struct {
+	Subscriptions []string             `json:"subscriptions,omitempty"`
+	Query         string               `json:"query"`
+	Options       ResourceGraphOptions `json:"options"`
+}
+
+type ResourceGraphOptions struct {
+	ResultFormat string `json:"resultFormat"`
+	Top          int    `json:"$top,omitempty"`
+	SkipToken    string `json:"$skipToken,omitempty"`
+}
+
+// ResourceGraphResponse is a page of query results in the table result format
+type ResourceGraphResponse struct {
+	TotalRecords int               `json:"totalRecords"`
+	Count        int               `json:"count"`
+	Data         ResourceGraphData `json:"data"`
+	SkipToken    string            `json:"$skipToken"`
+}
+
+type ResourceGraphData struct {
+	Columns []ResourceGraphColumn `json:"columns"`
+	Rows    [][]interface{}       `json:"rows"`
+}
+
+type ResourceGraphColumn struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
@@ -0,0 +1,45 @@
+package models
+
+type StorageAccount struct {
+	ID         string                   `json:"id"`
+	Name       string                   `json:"name"`
+	Location   string                   `json:"location"`
+	Properties StorageAccountProperties `json:"properties"`
+}
+
+// StorageAccountProperties holds the security relevant settings of a storage account.
+// Pointer fields are nil when the API omits them, which means the platform default applies.
+type StorageAccountProperties struct {
+	AllowBlobPublicAccess    *bool              `json:"allowBlobPublicAccess"`
+	AllowSharedKeyAccess     *bool              `json:"allowSharedKeyAccess"`
+	MinimumTLSVersion        string             `json:"minimumTlsVersion"`
+	SupportsHTTPSTrafficOnly *bool              `json:"supportsHttpsTrafficOnly"`
+	NetworkACLs              *StorageNetworkACL `json:"networkAcls"`
+	SASPolicy                *StorageSASPolicy  `json:"sasPolicy"`
+	Encryption               *StorageEncryption `json:"encryption"`
+}
+
+type StorageNetworkACL struct {
+	DefaultAction string `json:"defaultAction"`
+	Bypass        string `json:"bypass"`
+}
+
+type StorageSASPolicy struct {
+	SASExpirationPeriod string `json:"sasExpirationPeriod"`
+	ExpirationAction    string `json:"expirationAction"`
+}
+
+type StorageEncryption struct {
+	RequireInfrastructureEncryption *bool  `json:"requireInfrastructureEncryption"`
+	KeySource                       string `json:"keySource"`
+}
+
+type StorageAccountKeysResponse struct {
+	Keys []StorageAccountKey `json:"keys"`
+}
+
+type StorageAccountKey struct {
+	KeyName     string `json:"keyName"`
+	Value       string `json:"value"`
+	Permissions string `json:"permissions"`
+}
@@ -0,0 +1,140 @@
+package rbac
+
+import (
+	"strings"
+
+	"github.com/f0rk3b0mb/GoCloudGhost/azure/models"
+)
+
+// ActionMatches reports whether an Azure RBAC action pattern matches an operation.
+// Matching is case-insensitive and * matches any sequence of characters, including "/".
+func ActionMatches(pattern, operation string) bool {
+	pattern = strings.ToLower(pattern)
+	operation = strings.ToLower(operation)
+
+	// Iterative glob match with single-star backtracking
+	p, o := 0, 0
+	star, mark := -1, 0
+	for o < len(operation) {
+		switch {
+		case p < len(pattern) && pattern[p] == '*':
+			star, mark = p, o
+			p++
+		case p < len(pattern) && pattern[p] == operation[o]:
+			p++
+			o++
+		case star != -1:
+			p = star + 1
+			mark++
+			o = mark
+		default:
+			return false
+		}
+	}
+
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+
+	return p == len(pattern)
+}
+
+// FirstMatch returns the first pattern in the list that matches the operation
+func FirstMatch(patterns []string, operation string) (string, bool) {
+	for _, pattern := range patterns {
+		if ActionMatches(pattern, operation) {
+			return pattern, true
+		}
+	}
+	return "", false
+}
+
+// Allows reports whether any permission entry grants the operation without its own
+// not-list excluding it, returning the granting pattern. Data plane operations are
+// evaluated against dataActions and notDataActions. A wildcard in the operation is
+// treated literally, so the grant must cover the whole pattern.
+func Allows(permissions []models.Permission, operation string, data bool) (string, bool) {
+	for _, perm := range permissions {
+		grants, excludes := perm.Actions, perm.NotActions
+		if data {
+			grants, excludes = perm.DataActions, perm.NotDataActions
+		}
+
+		grant, ok := FirstMatch(grants, operation)
+		if !ok {
+			continue
+		}
+		if _, excluded := FirstMatch(excludes, operation); excluded {
+			continue
+		}
+		return grant, true
+	}
+
+	return "", false
+}
+
+// IneffectiveNotActions returns notActions and notDataActions entries that do not
+// overlap any action granted by the same permission entry and therefore exclude nothing
+func IneffectiveNotActions(permissions []models.Permission) []string {
+	var ineffective []string
+
+	for _, perm := range permissions {
+		ineffective = append(ineffective, unusedExclusions(perm.Actions, perm.NotActions)...)
+		ineffective = append(ineffective, unusedExclusions(perm.DataActions, perm.NotDataActions)...)
+	}
+
+	return ineffective
+}
+
+func unusedExclusions(grants, excludes []string) []string {
+	var unused []string
+
+	for _, exclude := range excludes {
+		overlaps := false
+		for _, grant := range grants {
+			if patternsOverlap(grant, exclude) {
+				overlaps = true
+				break
+			}
+		}
+		if !overlaps {
+			unused = append(unused, exclude)
+		}
+	}
+
+	return unused
+}
+
+// patternsOverlap reports whether two wildcard patterns can match a common operation.
+// Both sides may contain *, so neither pattern can be treated as a concrete operation.
+func patternsOverlap(a, b string) bool {
+	a, b = strings.ToLower(a), strings.ToLower(b)
+
+	// seen memoizes failed states, every (i, j) pair is explored at most once
+	seen := make(map[[2]int]bool)
+
+	var overlap func(i, j int) bool
+	overlap = func(i, j int) bool {
+		if i == len(a) && j == len(b) {
+			return true
+		}
+		state := [2]int{i, j}
+		if seen[state] {
+			return false
+		}
+		seen[state] = true
+
+		switch {
+		case i < len(a) && a[i] == '*':
+			// The star matches nothing, or absorbs the next character of b
+			return overlap(i+1, j) || (j < len(b) && overlap(i, j+1))
+		case j < len(b) && b[j] == '*':
+			return overlap(i, j+1) || (i < len(a) && overlap(i+1, j))
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			return overlap(i+1, j+1)
+		}
+		return false
+	}
+
+	return overlap(0, 0)
+}
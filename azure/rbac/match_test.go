@@ -0,0 +1,215 @@
+package rbac
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/f0rk3b0mb/GoCloudGhost/azure/models"
+)
+
+func TestActionMatches(t *testing.T) {
+	tests := []struct {
+		pattern   string
+		operation string
+		want      bool
+	}{
+		{"*", "Microsoft.Authorization/roleAssignments/write", true},
+		{"Microsoft.Authorization/*", "Microsoft.Authorization/roleAssignments/write", true},
+		{"microsoft.authorization/*", "Microsoft.Authorization/roleAssignments/write", true},
+		{"Microsoft.Authorization/*/write", "Microsoft.Authorization/roleAssignments/write", true},
+		{"*/write", "Microsoft.Authorization/roleAssignments/write", true},
+		{"*/read", "Microsoft.Authorization/roleAssignments/write", false},
+		{"Microsoft.Authorization/roleAssignments/write", "Microsoft.Authorization/roleAssignments/write", true},
+		{"Microsoft.Authorization/roleAssignments/read", "Microsoft.Authorization/roleAssignments/write", false},
+		{"Microsoft.Authorization/roleAssignments", "Microsoft.Authorization/roleAssignments/write", false},
+		{"Microsoft.Compute/*", "Microsoft.Authorization/roleAssignments/write", false},
+		{"Microsoft.Authorization/roleAssignments/write/*", "Microsoft.Authorization/roleAssignments/write", false},
+		{"", "Microsoft.Authorization/roleAssignments/write", false},
+	}
+
+	for _, tt := range tests {
+		if got := ActionMatches(tt.pattern, tt.operation); got != tt.want {
+			t.Errorf("ActionMatches(%q, %q) = %v, want %v", tt.pattern, tt.operation, got, tt.want)
+		}
+	}
+}
+
+func TestAllows(t *testing.T) {
+	const assignRoles = "Microsoft.Authorization/roleAssignments/write"
+	const readBlobs = "Microsoft.Storage/storageAccounts/blobServices/containers/blobs/read"
+
+	tests := []struct {
+		name        string
+		permissions []models.Permission
+		operation   string
+		data        bool
+		wantGrant   string
+		wantAllowed bool
+	}{
+		{
+			name:        "wildcard grants everything",
+			permissions: []models.Permission{{Actions: []string{"*"}}},
+			operation:   assignRoles,
+			wantGrant:   "*",
+			wantAllowed: true,
+		},
+		{
+			name:        "provider wildcard",
+			permissions: []models.Permission{{Actions: []string{"Microsoft.Authorization/*"}}},
+			operation:   assignRoles,
+			wantGrant:   "Microsoft.Authorization/*",
+			wantAllowed: true,
+		},
+		{
+			name:        "exact action",
+			permissions: []models.Permission{{Actions: []string{"*/read", assignRoles}}},
+			operation:   assignRoles,
+			wantGrant:   assignRoles,
+			wantAllowed: true,
+		},
+		{
+			name:        "read only",
+			permissions: []models.Permission{{Actions: []string{"*/read"}}},
+			operation:   assignRoles,
+		},
+		{
+			name: "contributor excludes authorization writes",
+			permissions: []models.Permission{{
+				Actions:    []string{"*"},
+				NotActions: []string{"Microsoft.Authorization/*/Delete", "Microsoft.Authorization/*/Write"},
+			}},
+			operation: assignRoles,
+		},
+		{
+			name: "exclusion of another operation does not apply",
+			permissions: []models.Permission{{
+				Actions:    []string{"*"},
+				NotActions: []string{"Microsoft.Authorization/roleAssignments/delete"},
+			}},
+			operation:   assignRoles,
+			wantGrant:   "*",
+			wantAllowed: true,
+		},
+		{
+			name: "exclusion only applies to its own permission entry",
+			permissions: []models.Permission{
+				{Actions: []string{"*"}, NotActions: []string{"Microsoft.Authorization/*"}},
+				{Actions: []string{"Microsoft.Authorization/roleAssignments/*"}},
+			},
+			operation:   assignRoles,
+			wantGrant:   "Microsoft.Authorization/roleAssignments/*",
+			wantAllowed: true,
+		},
+		{
+			name:        "actions do not grant data actions",
+			permissions: []models.Permission{{Actions: []string{"*"}}},
+			operation:   readBlobs,
+			data:        true,
+		},
+		{
+			name:        "data action wildcard",
+			permissions: []models.Permission{{DataActions: []string{"Microsoft.Storage/*"}}},
+			operation:   readBlobs,
+			data:        true,
+			wantGrant:   "Microsoft.Storage/*",
+			wantAllowed: true,
+		},
+		{
+			name:        "data actions do not grant control plane actions",
+			permissions: []models.Permission{{DataActions: []string{"*"}}},
+			operation:   assignRoles,
+		},
+		{
+			name: "not data actions exclude",
+			permissions: []models.Permission{{
+				DataActions:    []string{"Microsoft.Storage/storageAccounts/blobServices/containers/blobs/*"},
+				NotDataActions: []string{"Microsoft.Storage/storageAccounts/blobServices/containers/blobs/read"},
+			}},
+			operation: readBlobs,
+			data:      true,
+		},
+		{
+			name: "not actions do not exclude data actions",
+			permissions: []models.Permission{{
+				DataActions: []string{"*"},
+				NotActions:  []string{"*"},
+			}},
+			operation:   readBlobs,
+			data:        true,
+			wantGrant:   "*",
+			wantAllowed: true,
+		},
+		{
+			name:      "no permissions",
+			operation: assignRoles,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			grant, allowed := Allows(tt.permissions, tt.operation, tt.data)
+			if grant != tt.wantGrant || allowed != tt.wantAllowed {
+				t.Errorf("Allows() = (%q, %v), want (%q, %v)", grant, allowed, tt.wantGrant, tt.wantAllowed)
+			}
+		})
+	}
+}
+
+func TestPatternsOverlap(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"*", "Microsoft.Authorization/*", true},
+		{"*/read", "Microsoft.Authorization/*", true},
+		{"Microsoft.*/write", "*/vaults/*", true},
+		{"Microsoft.Authorization/*/Write", "Microsoft.Authorization/roleAssignments/write", true},
+		{"Microsoft.Compute/*", "Microsoft.Authorization/*", false},
+		{"*/read", "*/write", false},
+		{"Microsoft.Storage/*/read", "Microsoft.Storage/*/delete", false},
+		{"Microsoft.KeyVault/vaults/read", "Microsoft.KeyVault/vaults/read", true},
+		{"Microsoft.KeyVault/vaults/read", "Microsoft.KeyVault/vaults/write", false},
+		{"Microsoft.Web/*", "Microsoft.Web", false},
+	}
+
+	for _, tt := range tests {
+		if got := patternsOverlap(tt.a, tt.b); got != tt.want {
+			t.Errorf("patternsOverlap(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+		if got := patternsOverlap(tt.b, tt.a); got != tt.want {
+			t.Errorf("patternsOverlap(%q, %q) = %v, want %v", tt.b, tt.a, got, tt.want)
+		}
+	}
+}
+
+func TestIneffectiveNotActions(t *testing.T) {
+	tests := []struct {
+		name       string
+		permission models.Permission
+		want       []string
+	}{
+		{
+			name:       "exclusion inside a wildcard grant",
+			permission: models.Permission{Actions: []string{"*/read"}, NotActions: []string{"Microsoft.Authorization/*"}},
+		},
+		{
+			name:       "exclusion of an ungranted provider",
+			permission: models.Permission{Actions: []string{"Microsoft.Compute/*"}, NotActions: []string{"Microsoft.Authorization/*/Write"}},
+			want:       []string{"Microsoft.Authorization/*/Write"},
+		},
+		{
+			name:       "data exclusion is checked against data actions",
+			permission: models.Permission{Actions: []string{"*"}, NotDataActions: []string{"Microsoft.Storage/*"}},
+			want:       []string{"Microsoft.Storage/*"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := IneffectiveNotActions([]models.Permission{tt.permission})
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("IneffectiveNotActions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
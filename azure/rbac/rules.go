@@ -0,0 +1,152 @@
+package rbac
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/f0rk3b0mb/GoCloudGhost/azure/models"
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed rules.yaml
+var defaultRules []byte
+
+// Rule describes a high-risk capability in terms of the operations that grant it
+type Rule struct {
+	ID          string   `yaml:"id"`
+	Title       string   `yaml:"title"`
+	Severity    string   `yaml:"severity"`
+	Actions     []string `yaml:"actions"`
+	DataActions []string `yaml:"dataActions"`
+}
+
+type ruleFile struct {
+	Rules []Rule `yaml:"rules"`
+}
+
+// Match is a rule fired by a set of permissions, with the operation and grant that triggered it
+type Match struct {
+	Rule      Rule
+	Operation string
+	Grant     string
+}
+
+// Engine evaluates role permissions against a catalogue of rules
+type Engine struct {
+	Rules []Rule
+}
+
+// DefaultEngine returns an engine loaded with the built-in rule catalogue
+func DefaultEngine() *Engine {
+	rules, err := parseRules(defaultRules)
+	if err != nil {
+		panic(fmt.Sprintf("invalid built-in RBAC rules: %v", err))
+	}
+	return &Engine{Rules: rules}
+}
+
+// NewEngine returns an engine with the built-in rules plus the rules in path, if given.
+// A user rule with the same ID as a built-in rule replaces it.
+func NewEngine(path string) (*Engine, error) {
+	engine := DefaultEngine()
+	if path == "" {
+		return engine, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules file: %w", err)
+	}
+
+	extra, err := parseRules(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rules file %s: %w", path, err)
+	}
+
+	for _, rule := range extra {
+		replaced := false
+		for i := range engine.Rules {
+			if engine.Rules[i].ID == rule.ID {
+				engine.Rules[i] = rule
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			engine.Rules = append(engine.Rules, rule)
+		}
+	}
+
+	return engine, nil
+}
+
+func parseRules(data []byte) ([]Rule, error) {
+	var file ruleFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+
+	for i, rule := range file.Rules {
+		if rule.ID == "" {
+			return nil, fmt.Errorf("rule %d has no id", i+1)
+		}
+		if len(rule.Actions) == 0 && len(rule.DataActions) == 0 {
+			return nil, fmt.Errorf("rule %s has no actions or dataActions", rule.ID)
+		}
+
+		severity := strings.ToUpper(rule.Severity)
+		if models.SeverityRank(severity) == 0 {
+			return nil, fmt.Errorf("rule %s has unknown severity %q", rule.ID, rule.Severity)
+		}
+		file.Rules[i].Severity = severity
+	}
+
+	return file.Rules, nil
+}
+
+// Evaluate returns every rule whose operations are granted by the permissions
+func (e *Engine) Evaluate(permissions []models.Permission) []Match {
+	var matches []Match
+
+	for _, rule := range e.Rules {
+		if match, ok := evaluateRule(rule, permissions); ok {
+			matches = append(matches, match)
+		}
+	}
+
+	return matches
+}
+
+// EvaluateRole returns every rule fired by a role definition
+func (e *Engine) EvaluateRole(role models.RoleDefinition) []Match {
+	return e.Evaluate(role.Properties.Permissions)
+}
+
+func evaluateRule(rule Rule, permissions []models.Permission) (Match, bool) {
+	for _, operation := range rule.Actions {
+		if grant, ok := Allows(permissions, operation, false); ok {
+			return Match{Rule: rule, Operation: operation, Grant: grant}, true
+		}
+	}
+
+	for _, operation := range rule.DataActions {
+		if grant, ok := Allows(permissions, operation, true); ok {
+			return Match{Rule: rule, Operation: operation, Grant: grant}, true
+		}
+	}
+
+	return Match{}, false
+}
+
+// HighestSeverity returns the most severe severity among the matches, or "" if there are none
+func HighestSeverity(matches []Match) string {
+	highest := ""
+	for _, match := range matches {
+		if models.SeverityRank(match.Rule.Severity) > models.SeverityRank(highest) {
+			highest = match.Rule.Severity
+		}
+	}
+	return highest
+}
@@ -0,0 +1,146 @@
+package rbac
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/f0rk3b0mb/GoCloudGhost/azure/models"
+)
+
+func TestEvaluate(t *testing.T) {
+	engine := &Engine{Rules: []Rule{
+		{ID: "assign", Severity: models.SeverityCritical, Actions: []string{"Microsoft.Authorization/roleAssignments/write"}},
+		{ID: "any-write", Severity: models.SeverityMedium, Actions: []string{"*/write"}},
+		{ID: "blob-read", Severity: models.SeverityHigh, DataActions: []string{"Microsoft.Storage/storageAccounts/blobServices/containers/blobs/read"}},
+	}}
+
+	tests := []struct {
+		name        string
+		permissions []models.Permission
+		want        []string
+		wantHighest string
+	}{
+		{
+			name:        "owner",
+			permissions: []models.Permission{{Actions: []string{"*"}}},
+			want:        []string{"any-write", "assign"},
+			wantHighest: models.SeverityCritical,
+		},
+		{
+			name: "contributor",
+			permissions: []models.Permission{{
+				Actions:    []string{"*"},
+				NotActions: []string{"Microsoft.Authorization/*/Write", "Microsoft.Authorization/*/Delete"},
+			}},
+			want:        []string{"any-write"},
+			wantHighest: models.SeverityMedium,
+		},
+		{
+			name:        "custom authorization role",
+			permissions: []models.Permission{{Actions: []string{"Microsoft.Authorization/*"}}},
+			want:        []string{"assign"},
+			wantHighest: models.SeverityCritical,
+		},
+		{
+			// A wildcard operation must be covered in full, a provider wildcard is not enough
+			name:        "provider wildcard does not cover */write",
+			permissions: []models.Permission{{Actions: []string{"Microsoft.Compute/*"}}},
+		},
+		{
+			name:        "reader",
+			permissions: []models.Permission{{Actions: []string{"*/read"}}},
+		},
+		{
+			name:        "blob data reader",
+			permissions: []models.Permission{{DataActions: []string{"Microsoft.Storage/storageAccounts/blobServices/containers/blobs/read"}}},
+			want:        []string{"blob-read"},
+			wantHighest: models.SeverityHigh,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches := engine.Evaluate(tt.permissions)
+
+			var got []string
+			for _, match := range matches {
+				got = append(got, match.Rule.ID)
+			}
+			sort.Strings(got)
+
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Evaluate() fired %v, want %v", got, tt.want)
+			}
+			if highest := HighestSeverity(matches); highest != tt.wantHighest {
+				t.Errorf("HighestSeverity() = %q, want %q", highest, tt.wantHighest)
+			}
+		})
+	}
+}
+
+func TestDefaultEngine(t *testing.T) {
+	engine := DefaultEngine()
+
+	owner := models.RoleDefinition{Properties: models.RoleDefinitionProperties{
+		RoleName:    "Owner",
+		Permissions: []models.Permission{{Actions: []string{"*"}}},
+	}}
+	if highest := HighestSeverity(engine.EvaluateRole(owner)); highest != models.SeverityCritical {
+		t.Errorf("Owner evaluated to %q, want %q", highest, models.SeverityCritical)
+	}
+
+	reader := models.RoleDefinition{Properties: models.RoleDefinitionProperties{
+		RoleName:    "Reader",
+		Permissions: []models.Permission{{Actions: []string{"*/read"}}},
+	}}
+	if matches := engine.EvaluateRole(reader); len(matches) != 0 {
+		t.Errorf("Reader fired %d rules, want none", len(matches))
+	}
+}
+
+func TestParseRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{
+			name: "valid",
+			data: "rules:\n  - id: a\n    severity: high\n    actions: [\"*/write\"]\n",
+		},
+		{
+			name:    "missing id",
+			data:    "rules:\n  - severity: HIGH\n    actions: [\"*/write\"]\n",
+			wantErr: "has no id",
+		},
+		{
+			name:    "missing operations",
+			data:    "rules:\n  - id: a\n    severity: HIGH\n",
+			wantErr: "has no actions or dataActions",
+		},
+		{
+			name:    "unknown severity",
+			data:    "rules:\n  - id: a\n    severity: urgent\n    actions: [\"*/write\"]\n",
+			wantErr: "unknown severity",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := parseRules([]byte(tt.data))
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("parseRules() error = %v", err)
+				}
+				if rules[0].Severity != models.SeverityHigh {
+					t.Errorf("severity = %q, want it normalized to %q", rules[0].Severity, models.SeverityHigh)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("parseRules() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
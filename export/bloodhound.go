@@ -0,0 +1,518 @@
+package export
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/f0rk3b0mb/GoCloudGhost/azure/models"
+	gcplist "github.com/f0rk3b0mb/GoCloudGhost/gcp/list"
+	"github.com/f0rk3b0mb/GoCloudGhost/session"
+	"github.com/spf13/cobra"
+)
+
+// azureHoundVersion is the AzureHound output format version BloodHound CE accepts
+const azureHoundVersion = 5
+
+// Built-in role definition GUIDs that AzureHound reports in their own collections
+const (
+	ownerRoleID           = "8e3af657-a8ff-443c-a75c-2fe8c4bcb635"
+	userAccessAdminRoleID = "18d7d88d-d35e-4fb5-a5c3-7773c20a72d9"
+)
+
+// Lower-cased resource types and scope prefixes used to pick an AzureHound collection
+const (
+	keyVaultTypeLower         = "microsoft.keyvault/vaults"
+	storageAccountTypeLower   = "microsoft.storage/storageaccounts"
+	managementGroupScopeLower = "/providers/microsoft.management/managementgroups/"
+)
+
+var bloodhoundCmd = &cobra.Command{
+	Use:   "bloodhound",
+	Short: "Export collected Azure data as AzureHound JSON and GCP impersonation as OpenGraph JSON for BloodHound CE",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		output, _ := cmd.Flags().GetString("output")
+		tenantID, _ := cmd.Flags().GetString("tenant")
+		return exportBloodHound(output, resolveTenantID(tenantID))
+	},
+}
+
+func init() {
+	bloodhoundCmd.Flags().String("tenant", "", "Tenant ID of the collected Azure data (defaults to the token's tid)")
+}
+
+type azureHoundFile struct {
+	Data []azureHoundItem `json:"data"`
+	Meta azureHoundMeta   `json:"meta"`
+}
+
+type azureHoundItem struct {
+	Kind string      `json:"kind"`
+	Data interface{} `json:"data"`
+}
+
+type azureHoundMeta struct {
+	Methods int    `json:"methods"`
+	Type    string `json:"type"`
+	Count   int    `json:"count"`
+	Version int    `json:"version"`
+}
+
+// object is a JSON object in the AzureHound schema
+type object = map[string]interface{}
+
+func exportBloodHound(outputDir, tenantID string) error {
+	data, err := loadAzureSession()
+	if err != nil {
+		return err
+	}
+
+	var impersonation []gcplist.ImpersonationResult
+	if _, err := session.Load(gcplist.ImpersonationSession, &impersonation); err != nil {
+		return err
+	}
+
+	fmt.Println("\n=== BLOODHOUND EXPORT ===")
+
+	azure := buildAzureHound(data, tenantID)
+	if len(azure.Data) > 1 {
+		if tenantID == "" {
+			fmt.Println("[WARN] Tenant ID unknown, pass --tenant so BloodHound can link the objects to a tenant")
+		}
+
+		path, err := writeJSON(outputDir, "azurehound.json", azure)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("[INFO] Azure: %d objects written to %s\n", azure.Meta.Count, path)
+	} else {
+		fmt.Println("[INFO] No Azure data in the session.")
+	}
+
+	if len(impersonation) > 0 {
+		path, err := writeJSON(outputDir, "gcp-opengraph.json", buildGCPOpenGraph(impersonation))
+		if err != nil {
+			return err
+		}
+		fmt.Printf("[INFO] GCP: impersonation results of %d projects written to %s\n", len(impersonation), path)
+	} else {
+		fmt.Println("[INFO] No GCP impersonation results in the session.")
+	}
+
+	return nil
+}
+
+// azureHoundBuilder accumulates AzureHound records, emitting each object once
+type azureHoundBuilder struct {
+	tenantID string
+	items    []azureHoundItem
+	emitted  map[string]bool
+}
+
+func (b *azureHoundBuilder) add(kind string, data object) {
+	b.items = append(b.items, azureHoundItem{Kind: kind, Data: data})
+}
+
+// addOnce adds an object record unless one with the same kind and ID was already added
+func (b *azureHoundBuilder) addOnce(kind, id string, data object) {
+	key := kind + "|" + strings.ToLower(id)
+	if b.emitted[key] {
+		return
+	}
+	b.emitted[key] = true
+
+	data["tenantId"] = b.tenantID
+	b.add(kind, data)
+}
+
+func buildAzureHound(data *azureSession, tenantID string) azureHoundFile {
+	b := &azureHoundBuilder{tenantID: tenantID, emitted: make(map[string]bool)}
+
+	b.add("AZTenant", object{"id": tenantID, "tenantId": tenantID, "collected": true})
+
+	// Full directory objects first, so principals only known by ID do not shadow them
+	for _, user := range data.Users {
+		b.addOnce("AZUser", user.ID, object{
+			"id":                user.ID,
+			"displayName":       user.DisplayName,
+			"userPrincipalName": user.UserPrincipalName,
+			"mail":              user.Mail,
+			"userType":          user.UserType,
+			"accountEnabled":    user.AccountEnabled,
+		})
+	}
+	for _, group := range data.Groups {
+		b.addOnce("AZGroup", group.ID, object{
+			"id":                 group.ID,
+			"displayName":        group.DisplayName,
+			"description":        group.Description,
+			"securityEnabled":    group.SecurityEnabled,
+			"isAssignableToRole": group.IsAssignableToRole,
+		})
+	}
+	for _, sp := range data.ServicePrincipals {
+		b.addOnce("AZServicePrincipal", sp.ID, object{
+			"id":                     sp.ID,
+			"appId":                  sp.AppID,
+			"displayName":            sp.DisplayName,
+			"servicePrincipalType":   sp.ServicePrincipalType,
+			"appOwnerOrganizationId": sp.AppOwnerOrganizationID,
+			"accountEnabled":         sp.AccountEnabled,
+		})
+	}
+	for _, app := range data.Applications {
+		b.addOnce("AZApp", app.ID, object{"id": app.ID, "appId": app.AppID, "displayName": app.DisplayName})
+	}
+
+	for _, principal := range sortedPrincipals(data.Principals) {
+		b.addPrincipal(principal)
+	}
+
+	b.addGroupMembers(data.Groups)
+	b.addOwners(data.AppAudits)
+	b.addDirectoryRoles(data.DirectoryRoles)
+
+	if data.ManagementGroups.ID != "" {
+		b.addManagementGroupTree(data.ManagementGroups, "")
+	}
+
+	resourceTypes := make(map[string]string, len(data.Resources))
+	for _, resource := range data.Resources {
+		resourceTypes[strings.ToLower(resource.ID)] = strings.ToLower(resource.Type)
+		b.addResource(resource)
+	}
+
+	b.addRoleAssignments(data.RoleAssignments, resourceTypes)
+
+	return azureHoundFile{
+		Data: b.items,
+		Meta: azureHoundMeta{Type: "azure", Count: len(b.items), Version: azureHoundVersion},
+	}
+}
+
+// addPrincipal adds a principal only known from role assignments, memberships or ownership
+func (b *azureHoundBuilder) addPrincipal(principal models.Principal) {
+	record := object{"id": principal.ID, "displayName": principal.DisplayName}
+
+	switch principal.Type {
+	case models.PrincipalTypeUser:
+		record["userPrincipalName"] = principal.Identifier
+		b.addOnce("AZUser", principal.ID, record)
+	case models.PrincipalTypeGroup:
+		b.addOnce("AZGroup", principal.ID, record)
+	case models.PrincipalTypeServicePrincipal, models.PrincipalTypeManagedIdentity:
+		record["appId"] = principal.Identifier
+		if principal.Type == models.PrincipalTypeManagedIdentity {
+			record["servicePrincipalType"] = "ManagedIdentity"
+		}
+		b.addOnce("AZServicePrincipal", principal.ID, record)
+	}
+}
+
+func (b *azureHoundBuilder) addGroupMembers(groups []models.GraphGroup) {
+	for _, group := range groups {
+		for _, member := range group.Members {
+			b.addPrincipal(member)
+			b.add("AZGroupMember", object{
+				"groupId": group.ID,
+				"member":  directoryObject(member),
+			})
+		}
+	}
+}
+
+func (b *azureHoundBuilder) addOwners(audits []models.AppAudit) {
+	for _, audit := range audits {
+		if audit.Kind == models.AppAuditKindApplication {
+			b.addOnce("AZApp", audit.ObjectID, object{"id": audit.ObjectID, "appId": audit.AppID, "displayName": audit.DisplayName})
+		} else {
+			b.addOnce("AZServicePrincipal", audit.ObjectID, object{
+				"id":                   audit.ObjectID,
+				"appId":                audit.AppID,
+				"displayName":          audit.DisplayName,
+				"servicePrincipalType": audit.ServicePrincipalType,
+			})
+		}
+
+		if len(audit.Owners) == 0 {
+			continue
+		}
+
+		if audit.Kind == models.AppAuditKindApplication {
+			owners := make([]object, 0, len(audit.Owners))
+			for _, owner := range audit.Owners {
+				b.addPrincipal(owner)
+				owners = append(owners, object{"appId": audit.ObjectID, "owner": directoryObject(owner)})
+			}
+			b.add("AZAppOwner", object{"appId": audit.ObjectID, "owners": owners})
+			continue
+		}
+
+		owners := make([]object, 0, len(audit.Owners))
+		for _, owner := range audit.Owners {
+			b.addPrincipal(owner)
+			owners = append(owners, object{"servicePrincipalId": audit.ObjectID, "owner": directoryObject(owner)})
+		}
+		b.add("AZServicePrincipalOwner", object{"servicePrincipalId": audit.ObjectID, "owners": owners})
+	}
+}
+
+func (b *azureHoundBuilder) addDirectoryRoles(assignments []models.DirectoryRoleAssignment) {
+	byRole := make(map[string][]models.DirectoryRoleAssignment)
+	var templates []string
+
+	for _, assignment := range assignments {
+		if _, ok := byRole[assignment.RoleTemplateID]; !ok {
+			templates = append(templates, assignment.RoleTemplateID)
+		}
+		byRole[assignment.RoleTemplateID] = append(byRole[assignment.RoleTemplateID], assignment)
+	}
+	sort.Strings(templates)
+
+	for _, templateID := range templates {
+		roleAssignments := byRole[templateID]
+
+		b.addOnce("AZRole", templateID, object{
+			"id":          templateID,
+			"templateId":  templateID,
+			"displayName": roleAssignments[0].RoleName,
+			"isBuiltIn":   true,
+		})
+
+		records := make([]object, 0, len(roleAssignments))
+		for _, assignment := range roleAssignments {
+			b.addPrincipal(assignment.Principal)
+			records = append(records, object{
+				"id":               templateID + "_" + assignment.Principal.ID + "_" + assignment.Scope,
+				"roleDefinitionId": templateID,
+				"principalId":      assignment.Principal.ID,
+				"directoryScopeId": assignment.Scope,
+			})
+		}
+
+		b.add("AZRoleAssignment", object{
+			"roleDefinitionId": templateID,
+			"tenantId":         b.tenantID,
+			"roleAssignments":  records,
+		})
+	}
+}
+
+func (b *azureHoundBuilder) addManagementGroupTree(node models.ManagementGroupChild, parentID string) {
+	if strings.EqualFold(node.Type, "/subscriptions") {
+		b.addSubscription(node.Name, node.DisplayName)
+	} else {
+		b.addOnce("AZManagementGroup", node.ID, object{
+			"id":   node.ID,
+			"name": node.Name,
+			"type": "Microsoft.Management/managementGroups",
+			"properties": object{
+				"displayName": node.DisplayName,
+				"tenantId":    b.tenantID,
+			},
+		})
+	}
+
+	if parentID != "" {
+		id := node.ID
+		if strings.EqualFold(node.Type, "/subscriptions") {
+			id = "/subscriptions/" + node.Name
+		}
+		b.add("AZManagementGroupDescendant", object{
+			"id":   id,
+			"name": node.Name,
+			"type": node.Type,
+			"properties": object{
+				"displayName": node.DisplayName,
+				"parent":      object{"id": parentID},
+			},
+		})
+	}
+
+	for _, child := range node.Children {
+		b.addManagementGroupTree(child, node.ID)
+	}
+}
+
+func (b *azureHoundBuilder) addSubscription(subscriptionID, displayName string) {
+	if displayName == "" {
+		displayName = subscriptionID
+	}
+	b.addOnce("AZSubscription", "/subscriptions/"+subscriptionID, object{
+		"id":             "/subscriptions/" + subscriptionID,
+		"subscriptionId": subscriptionID,
+		"displayName":    displayName,
+	})
+}
+
+func (b *azureHoundBuilder) addResourceGroup(id string) {
+	segments := strings.Split(strings.Trim(id, "/"), "/")
+	if len(segments) < 4 {
+		return
+	}
+
+	b.addSubscription(segments[1], "")
+	b.addOnce("AZResourceGroup", id, object{
+		"id":             id,
+		"name":           segments[3],
+		"subscriptionId": segments[1],
+	})
+}
+
+func (b *azureHoundBuilder) addResource(resource models.Resource) {
+	segments := strings.Split(strings.Trim(resource.ID, "/"), "/")
+	if len(segments) < 4 {
+		return
+	}
+
+	resourceGroupID := "/" + strings.Join(segments[:4], "/")
+	b.addResourceGroup(resourceGroupID)
+
+	record := object{
+		"id":             resource.ID,
+		"name":           resource.Name,
+		"type":           resource.Type,
+		"location":       resource.Location,
+		"subscriptionId": segments[1],
+	}
+
+	switch strings.ToLower(resource.Type) {
+	case keyVaultTypeLower:
+		record["resourceGroup"] = resourceGroupID
+		b.addOnce("AZKeyVault", resource.ID, record)
+	case storageAccountTypeLower:
+		record["resourceGroupId"] = resourceGroupID
+		record["resourceGroupName"] = segments[3]
+		b.addOnce("AZStorageAccount", resource.ID, record)
+	}
+}
+
+// scopeCollection describes the AzureHound collection role assignments at a scope belong to
+type scopeCollection struct {
+	prefix  string
+	idField string
+	// owners marks collections for which AzureHound also emits Owner and User Access Administrator lists
+	owners bool
+}
+
+func (b *azureHoundBuilder) addRoleAssignments(assignments []models.ResolvedRoleAssignment, resourceTypes map[string]string) {
+	byScope := make(map[string][]models.ResolvedRoleAssignment)
+	var scopes []string
+
+	for _, assignment := range assignments {
+		b.addPrincipal(assignment.Principal)
+
+		scope := strings.ToLower(assignment.Scope)
+		if _, ok := byScope[scope]; !ok {
+			scopes = append(scopes, scope)
+		}
+		byScope[scope] = append(byScope[scope], assignment)
+	}
+	sort.Strings(scopes)
+
+	skipped := 0
+	for _, scope := range scopes {
+		scopeAssignments := byScope[scope]
+		scopeID := scopeAssignments[0].Scope
+		segments := strings.Split(strings.Trim(scope, "/"), "/")
+
+		var collection scopeCollection
+		switch {
+		case strings.HasPrefix(scope, managementGroupScopeLower):
+			collection = scopeCollection{"AZManagementGroup", "managementGroupId", true}
+		case strings.HasPrefix(scope, "/subscriptions/") && len(segments) == 2:
+			b.addSubscription(strings.Split(strings.Trim(scopeID, "/"), "/")[1], "")
+			collection = scopeCollection{"AZSubscription", "subscriptionId", true}
+		case strings.HasPrefix(scope, "/subscriptions/") && len(segments) == 4:
+			b.addResourceGroup(scopeID)
+			collection = scopeCollection{"AZResourceGroup", "resourceGroupId", true}
+		case resourceTypes[scope] == keyVaultTypeLower:
+			collection = scopeCollection{"AZKeyVault", "keyVaultId", true}
+		case resourceTypes[scope] == storageAccountTypeLower:
+			collection = scopeCollection{"AZStorageAccount", "storageAccountId", false}
+		default:
+			// Root scope and resource types AzureHound does not model
+			skipped += len(scopeAssignments)
+			continue
+		}
+
+		b.addScopeAssignments(collection, scopeID, scopeAssignments)
+	}
+
+	if skipped > 0 {
+		fmt.Printf("[INFO] %d role assignments on scopes AzureHound does not model were skipped\n", skipped)
+	}
+}
+
+func (b *azureHoundBuilder) addScopeAssignments(collection scopeCollection, scopeID string, assignments []models.ResolvedRoleAssignment) {
+	var all, owners, admins []object
+
+	for _, assignment := range assignments {
+		record := roleAssignmentObject(assignment)
+		all = append(all, object{collection.idField: scopeID, "roleAssignment": record})
+
+		switch models.RoleDefinitionKey(assignment.RoleDefinitionID) {
+		case ownerRoleID:
+			owners = append(owners, object{collection.idField: scopeID, "owner": record})
+		case userAccessAdminRoleID:
+			admins = append(admins, object{collection.idField: scopeID, "userAccessAdmin": record})
+		}
+	}
+
+	b.add(collection.prefix+"RoleAssignment", object{collection.idField: scopeID, "roleAssignments": all})
+
+	if !collection.owners {
+		return
+	}
+	if len(owners) > 0 {
+		b.add(collection.prefix+"Owner", object{collection.idField: scopeID, "owners": owners})
+	}
+	if len(admins) > 0 {
+		b.add(collection.prefix+"UserAccessAdmin", object{collection.idField: scopeID, "userAccessAdmins": admins})
+	}
+}
+
+// roleAssignmentObject renders an assignment in the ARM shape AzureHound stores
+func roleAssignmentObject(assignment models.ResolvedRoleAssignment) object {
+	return object{
+		"id":   assignment.ID,
+		"name": assignment.ID[strings.LastIndex(assignment.ID, "/")+1:],
+		"type": "Microsoft.Authorization/roleAssignments",
+		"properties": object{
+			"roleDefinitionId": assignment.RoleDefinitionID,
+			"principalId":      assignment.Principal.ID,
+			"principalType":    assignment.Principal.Type,
+			"scope":            assignment.Scope,
+			"condition":        assignment.Condition,
+		},
+	}
+}
+
+// directoryObject renders a principal as a Graph directory object
+func directoryObject(principal models.Principal) object {
+	odataType := "#microsoft.graph.directoryObject"
+	switch principal.Type {
+	case models.PrincipalTypeUser:
+		odataType = "#microsoft.graph.user"
+	case models.PrincipalTypeGroup:
+		odataType = "#microsoft.graph.group"
+	case models.PrincipalTypeServicePrincipal, models.PrincipalTypeManagedIdentity:
+		odataType = "#microsoft.graph.servicePrincipal"
+	}
+
+	return object{"@odata.type": odataType, "id": principal.ID, "displayName": principal.DisplayName}
+}
+
+func sortedPrincipals(principals map[string]models.Principal) []models.Principal {
+	ids := make([]string, 0, len(principals))
+	for id := range principals {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	sorted := make([]models.Principal, 0, len(ids))
+	for _, id := range ids {
+		sorted = append(sorted, principals[id])
+	}
+	return sorted
+}
@@ -0,0 +1,133 @@
+package export
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/f0rk3b0mb/GoCloudGhost/attackpath"
+	"github.com/spf13/cobra"
+)
+
+// cypherBaseLabel is carried by every exported node so relationships can be matched by ID
+const cypherBaseLabel = "CloudObject"
+
+var cypherCmd = &cobra.Command{
+	Use:   "cypher",
+	Short: "Export principal, role and scope relationships as Neo4j Cypher MERGE statements",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		output, _ := cmd.Flags().GetString("output")
+
+		g, err := loadRelationships()
+		if err != nil {
+			return err
+		}
+
+		path, err := writeFile(output, "relationships.cypher", []byte(renderCypher(g)))
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("[INFO] %d nodes and %d edges written to %s\n", len(g.Nodes), len(g.Edges), path)
+		fmt.Printf("[INFO] Load with: cypher-shell -f %s\n", path)
+		return nil
+	},
+}
+
+// renderCypher writes idempotent MERGE statements, so the file can be loaded repeatedly
+// and on top of earlier exports
+func renderCypher(g *attackpath.Graph) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "CREATE INDEX cloud_object_id IF NOT EXISTS FOR (n:%s) ON (n.id);\n\n", cypherBaseLabel)
+
+	for _, node := range g.SortedNodes() {
+		fmt.Fprintf(&b, "MERGE (n:%s {id: %s}) SET n:%s, n.name = %s, n.kind = %s",
+			cypherBaseLabel,
+			cypherQuote(node.ID),
+			cypherLabel(node.Kind),
+			cypherQuote(node.Label()),
+			cypherQuote(node.Kind),
+		)
+		for _, key := range sortedKeys(node.Properties) {
+			if node.Properties[key] == "" {
+				continue
+			}
+			fmt.Fprintf(&b, ", n.%s = %s", cypherIdentifier(key), cypherQuote(node.Properties[key]))
+		}
+		b.WriteString(";\n")
+	}
+
+	b.WriteString("\n")
+
+	for _, edge := range sortedEdges(g) {
+		// The role is part of the relationship identity, one principal can hold several roles on a scope
+		identity := ""
+		if role, ok := edge.Properties["role"]; ok {
+			identity = " {role: " + cypherQuote(role) + "}"
+		}
+
+		fmt.Fprintf(&b, "MATCH (a:%s {id: %s}), (b:%s {id: %s}) MERGE (a)-[r:%s%s]->(b)",
+			cypherBaseLabel,
+			cypherQuote(edge.From),
+			cypherBaseLabel,
+			cypherQuote(edge.To),
+			relationshipType(edge.Kind),
+			identity,
+		)
+
+		var sets []string
+		for _, key := range sortedKeys(edge.Properties) {
+			if key == "role" {
+				continue
+			}
+			sets = append(sets, "r."+cypherIdentifier(key)+" = "+cypherQuote(edge.Properties[key]))
+		}
+		if len(sets) > 0 {
+			b.WriteString(" SET " + strings.Join(sets, ", "))
+		}
+		b.WriteString(";\n")
+	}
+
+	return b.String()
+}
+
+// cypherLabel prefixes Azure kinds so they do not collide with other data in the database
+func cypherLabel(kind string) string {
+	if strings.HasPrefix(kind, "GCP") {
+		return cypherIdentifier(kind)
+	}
+	return cypherIdentifier("Azure" + kind)
+}
+
+// relationshipType turns an edge kind such as HasRole into HAS_ROLE
+func relationshipType(kind string) string {
+	var b strings.Builder
+	for i, r := range kind {
+		if i > 0 && unicode.IsUpper(r) {
+			b.WriteRune('_')
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return cypherIdentifier(b.String())
+}
+
+func cypherIdentifier(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+func cypherQuote(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `'`, `\'`)
+	return "'" + value + "'"
+}
+
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,115 @@
+package export
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/f0rk3b0mb/GoCloudGhost/attackpath"
+	"github.com/spf13/cobra"
+)
+
+var dotCmd = &cobra.Command{
+	Use:   "dot",
+	Short: "Export principal, role and scope relationships as a Graphviz DOT file",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		output, _ := cmd.Flags().GetString("output")
+
+		g, err := loadRelationships()
+		if err != nil {
+			return err
+		}
+
+		path, err := writeFile(output, "relationships.dot", []byte(renderDOT(g)))
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("[INFO] %d nodes and %d edges written to %s\n", len(g.Nodes), len(g.Edges), path)
+		fmt.Printf("[INFO] Render with: dot -Tsvg %s -o relationships.svg\n", path)
+		return nil
+	},
+}
+
+// dotNodeStyles gives each kind of node a shape and fill colour
+var dotNodeStyles = map[string]string{
+	attackpath.KindUser:              `shape=ellipse, fillcolor="#cfe2f3"`,
+	attackpath.KindGroup:             `shape=ellipse, fillcolor="#d9ead3"`,
+	attackpath.KindServicePrincipal:  `shape=ellipse, fillcolor="#fff2cc"`,
+	attackpath.KindManagedIdentity:   `shape=ellipse, fillcolor="#fce5cd"`,
+	attackpath.KindManagementGroup:   `shape=folder, fillcolor="#d9d2e9"`,
+	attackpath.KindSubscription:      `shape=box3d, fillcolor="#d9d2e9"`,
+	attackpath.KindResourceGroup:     `shape=folder, fillcolor="#eeeeee"`,
+	attackpath.KindResource:          `shape=box, fillcolor="#ffffff"`,
+	attackpath.KindGCPProject:        `shape=folder, fillcolor="#d0e0e3"`,
+	attackpath.KindGCPUser:           `shape=ellipse, fillcolor="#cfe2f3"`,
+	attackpath.KindGCPServiceAccount: `shape=ellipse, fillcolor="#fff2cc"`,
+}
+
+// dotAccessColours colours role assignment edges by what the role allows
+var dotAccessColours = map[string]string{
+	attackpath.AccessOwner:      "red",
+	attackpath.AccessPrivileged: "orange",
+	attackpath.AccessRead:       "gray50",
+}
+
+func renderDOT(g *attackpath.Graph) string {
+	var b strings.Builder
+
+	b.WriteString("digraph GoCloudGhost {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [style=filled, fontname=\"Helvetica\", fontsize=10];\n")
+	b.WriteString("  edge [fontname=\"Helvetica\", fontsize=9];\n\n")
+
+	for _, node := range g.SortedNodes() {
+		style, ok := dotNodeStyles[node.Kind]
+		if !ok {
+			style = `shape=ellipse, fillcolor="#ffffff"`
+		}
+		fmt.Fprintf(&b, "  %s [label=%s, %s];\n", dotQuote(node.ID), dotQuote(node.Label()+"\n"+node.Kind), style)
+	}
+
+	b.WriteString("\n")
+
+	for _, edge := range sortedEdges(g) {
+		label := edge.Kind
+		colour := "black"
+
+		switch edge.Kind {
+		case attackpath.EdgeHasRole:
+			label = edge.Properties["role"]
+			colour = dotAccessColours[edge.Properties["access"]]
+		case attackpath.EdgeContains:
+			colour = "gray70"
+		case attackpath.EdgeCanImpersonate:
+			colour = "red"
+		}
+
+		fmt.Fprintf(&b, "  %s -> %s [label=%s, color=%s];\n", dotQuote(edge.From), dotQuote(edge.To), dotQuote(label), dotQuote(colour))
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func dotQuote(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	value = strings.ReplaceAll(value, "\n", `\n`)
+	return `"` + value + `"`
+}
+
+// sortedEdges returns the edges ordered by endpoints and kind, for stable exports
+func sortedEdges(g *attackpath.Graph) []attackpath.Edge {
+	edges := append([]attackpath.Edge(nil), g.Edges...)
+	sort.SliceStable(edges, func(i, j int) bool {
+		if edges[i].From != edges[j].From {
+			return edges[i].From < edges[j].From
+		}
+		if edges[i].To != edges[j].To {
+			return edges[i].To < edges[j].To
+		}
+		return edges[i].Kind < edges[j].Kind
+	})
+	return edges
+}
@@ -0,0 +1,148 @@
+// Package export converts the data collected in the session into formats understood by
+// graph tools such as BloodHound, Graphviz and Neo4j.
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/f0rk3b0mb/GoCloudGhost/attackpath"
+	"github.com/f0rk3b0mb/GoCloudGhost/azure/auth"
+	management "github.com/f0rk3b0mb/GoCloudGhost/azure/enum"
+	"github.com/f0rk3b0mb/GoCloudGhost/azure/graph"
+	"github.com/f0rk3b0mb/GoCloudGhost/azure/models"
+	"github.com/f0rk3b0mb/GoCloudGhost/azure/rbac"
+	"github.com/f0rk3b0mb/GoCloudGhost/session"
+	"github.com/joho/godotenv"
+	"github.com/spf13/cobra"
+)
+
+// defaultOutputDir keeps exports next to the other collected data, out of version control
+const defaultOutputDir = "loot/export"
+
+var ExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export collected Azure and GCP relationships for graph tools",
+}
+
+func init() {
+	ExportCmd.PersistentFlags().String("output", defaultOutputDir, "Directory to write the export files to")
+
+	ExportCmd.AddCommand(bloodhoundCmd)
+	ExportCmd.AddCommand(dotCmd)
+	ExportCmd.AddCommand(cypherCmd)
+}
+
+// loadRelationships builds a graph of the principal, role and scope relationships found by
+// `azure management --roles` and the service accounts found by `gcp list impersonate`
+func loadRelationships() (*attackpath.Graph, error) {
+	g := attackpath.New()
+
+	azure, err := attackpath.LoadRoleAssignments(g, rbac.DefaultEngine())
+	if err != nil {
+		return nil, err
+	}
+
+	gcp, err := attackpath.LoadGCPImpersonation(g)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(azure)+len(gcp) == 0 {
+		return nil, fmt.Errorf("no session data found, run `azure management --roles` or `gcp list impersonate` first")
+	}
+
+	return g, nil
+}
+
+// writeJSON writes v as indented JSON to name inside dir and returns the file path
+func writeJSON(dir, name string, v interface{}) (string, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode %s: %w", name, err)
+	}
+	return writeFile(dir, name, data)
+}
+
+func writeFile(dir, name string, data []byte) (string, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return path, nil
+}
+
+// resolveTenantID returns the tenant the collected Azure data belongs to, taken from the
+// ARM or Graph token and otherwise from the tenant root management group
+func resolveTenantID(tenantID string) string {
+	if tenantID != "" {
+		return tenantID
+	}
+
+	_ = godotenv.Load()
+	for _, variable := range []string{"ACCESS_TOKEN", "GRAPH_ACCESS_TOKEN"} {
+		token := os.Getenv(variable)
+		if token == "" {
+			continue
+		}
+		if claims, err := auth.ParseTokenClaims(token); err == nil && claims.TenantID != "" {
+			return claims.TenantID
+		}
+	}
+
+	var root models.ManagementGroupChild
+	if found, _ := session.Load(management.ManagementGroupsSession, &root); found {
+		return root.Name
+	}
+
+	return ""
+}
+
+// azureSession holds every Azure session entry the exports read. Entries that were
+// never collected are left empty.
+type azureSession struct {
+	Users             []models.GraphUser
+	Groups            []models.GraphGroup
+	ServicePrincipals []models.GraphServicePrincipal
+	Applications      []models.GraphApplication
+	Principals        map[string]models.Principal
+	AppAudits         []models.AppAudit
+	DirectoryRoles    []models.DirectoryRoleAssignment
+	ManagementGroups  models.ManagementGroupChild
+	Resources         []models.Resource
+	RoleAssignments   []models.ResolvedRoleAssignment
+}
+
+func loadAzureSession() (*azureSession, error) {
+	data := &azureSession{}
+
+	entries := []struct {
+		name string
+		v    interface{}
+	}{
+		{graph.UsersSession, &data.Users},
+		{graph.GroupsSession, &data.Groups},
+		{graph.ServicePrincipalsSession, &data.ServicePrincipals},
+		{graph.ApplicationsSession, &data.Applications},
+		{graph.PrincipalsSession, &data.Principals},
+		{graph.AppAuditSession, &data.AppAudits},
+		{graph.DirectoryRolesSession, &data.DirectoryRoles},
+		{management.ManagementGroupsSession, &data.ManagementGroups},
+		{management.ResourcesSession, &data.Resources},
+		{management.RoleAssignmentsSession, &data.RoleAssignments},
+	}
+
+	for _, entry := range entries {
+		if _, err := session.Load(entry.name, entry.v); err != nil {
+			return nil, err
+		}
+	}
+
+	return data, nil
+}
@@ -0,0 +1,110 @@
+package export
+
+import (
+	"strings"
+
+	gcplist "github.com/f0rk3b0mb/GoCloudGhost/gcp/list"
+)
+
+// AzureHound has no GCP object types, so GCP relationships are exported in BloodHound CE's
+// generic OpenGraph format under their own GCP kinds
+const openGraphSourceKind = "GCPBase"
+
+// OpenGraph node and edge kinds for GCP
+const (
+	kindGCPProject        = "GCPProject"
+	kindGCPUser           = "GCPUser"
+	kindGCPServiceAccount = "GCPServiceAccount"
+	edgeGCPContains       = "GCPContains"
+	edgeGCPCanImpersonate = "GCPCanImpersonate"
+)
+
+type openGraphFile struct {
+	Metadata openGraphMetadata `json:"metadata"`
+	Graph    openGraph         `json:"graph"`
+}
+
+type openGraphMetadata struct {
+	SourceKind string `json:"source_kind"`
+}
+
+type openGraph struct {
+	Nodes []openGraphNode `json:"nodes"`
+	Edges []openGraphEdge `json:"edges"`
+}
+
+type openGraphNode struct {
+	ID         string                 `json:"id"`
+	Kinds      []string               `json:"kinds"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+type openGraphEdge struct {
+	Kind  string            `json:"kind"`
+	Start openGraphEndpoint `json:"start"`
+	End   openGraphEndpoint `json:"end"`
+}
+
+type openGraphEndpoint struct {
+	Value   string `json:"value"`
+	MatchBy string `json:"match_by"`
+}
+
+func buildGCPOpenGraph(results []gcplist.ImpersonationResult) openGraphFile {
+	file := openGraphFile{Metadata: openGraphMetadata{SourceKind: openGraphSourceKind}}
+	seen := make(map[string]bool)
+
+	addNode := func(id, kind string, properties map[string]interface{}) {
+		if seen[id] {
+			return
+		}
+		seen[id] = true
+		properties["name"] = strings.ToUpper(id)
+		file.Graph.Nodes = append(file.Graph.Nodes, openGraphNode{
+			ID:         id,
+			Kinds:      []string{kind, openGraphSourceKind},
+			Properties: properties,
+		})
+	}
+	addEdge := func(kind, from, to string) {
+		file.Graph.Edges = append(file.Graph.Edges, openGraphEdge{
+			Kind:  kind,
+			Start: openGraphEndpoint{Value: from, MatchBy: "id"},
+			End:   openGraphEndpoint{Value: to, MatchBy: "id"},
+		})
+	}
+
+	for _, result := range results {
+		projectID := "projects/" + result.ProjectID
+		addNode(projectID, kindGCPProject, map[string]interface{}{"projectid": result.ProjectID})
+
+		caller := strings.ToLower(result.Caller)
+		if caller != "" {
+			addNode(caller, gcpPrincipalKind(caller), map[string]interface{}{"email": caller})
+		}
+
+		for _, account := range result.ServiceAccounts {
+			email := strings.ToLower(account.Email)
+			addNode(email, kindGCPServiceAccount, map[string]interface{}{
+				"email":       email,
+				"uniqueid":    account.UniqueID,
+				"displayname": account.DisplayName,
+				"projectid":   result.ProjectID,
+			})
+			addEdge(edgeGCPContains, projectID, email)
+
+			if account.Impersonable && caller != "" {
+				addEdge(edgeGCPCanImpersonate, caller, email)
+			}
+		}
+	}
+
+	return file
+}
+
+func gcpPrincipalKind(email string) string {
+	if strings.HasSuffix(email, ".gserviceaccount.com") {
+		return kindGCPServiceAccount
+	}
+	return kindGCPUser
+}
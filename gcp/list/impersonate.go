@@ -10,9 +10,28 @@ import (
 	"io"
 	"net/http"
 
+	"github.com/f0rk3b0mb/GoCloudGhost/session"
 	"github.com/spf13/cobra"
 )
 
+// ImpersonationSession is the session entry holding the impersonation check results
+const ImpersonationSession = "gcp_impersonation"
+
+// ImpersonationResult records which service accounts of a project a caller could impersonate
+type ImpersonationResult struct {
+	ProjectID string `json:"projectId"`
+	// Caller is the email of the token owner, empty when tokeninfo could not tell
+	Caller          string                 `json:"caller"`
+	ServiceAccounts []ServiceAccountResult `json:"serviceAccounts"`
+}
+
+type ServiceAccountResult struct {
+	Email        string `json:"email"`
+	UniqueID     string `json:"uniqueId"`
+	DisplayName  string `json:"displayName"`
+	Impersonable bool   `json:"impersonable"`
+}
+
 var TokenCmd = &cobra.Command{
 	Use:   "impersonate",
 	Short: "Check for token impersonation permissions",
@@ -92,6 +111,8 @@ func ListServiceAccounts(token string, projectID string) {
 
 	fmt.Println("\n[*] Checking for impersonation permissions...")
 
+	result := ImpersonationResult{ProjectID: projectID, Caller: tokenEmail(token)}
+
 	for _, acct := range accounts {
 		acctMap, ok := acct.(map[string]interface{})
 		if !ok {
@@ -102,8 +123,61 @@ func ListServiceAccounts(token string, projectID string) {
 			continue
 		}
 		fmt.Println("[*] Trying to impersonate:", email)
-		TokenImpersonate(email, token, projectID)
+		accessToken := TokenImpersonate(email, token, projectID)
+
+		uniqueID, _ := acctMap["uniqueId"].(string)
+		displayName, _ := acctMap["displayName"].(string)
+		result.ServiceAccounts = append(result.ServiceAccounts, ServiceAccountResult{
+			Email:        email,
+			UniqueID:     uniqueID,
+			DisplayName:  displayName,
+			Impersonable: accessToken != nil,
+		})
+	}
+
+	saveImpersonationResult(result)
+}
+
+// saveImpersonationResult stores the result in the session, replacing an earlier run for
+// the same project and caller
+func saveImpersonationResult(result ImpersonationResult) {
+	var results []ImpersonationResult
+	if _, err := session.Load(ImpersonationSession, &results); err != nil {
+		fmt.Println("[-] Could not load session:", err)
+		return
+	}
+
+	replaced := false
+	for i := range results {
+		if results[i].ProjectID == result.ProjectID && results[i].Caller == result.Caller {
+			results[i] = result
+			replaced = true
+		}
+	}
+	if !replaced {
+		results = append(results, result)
+	}
+
+	if err := session.Save(ImpersonationSession, results); err != nil {
+		fmt.Println("[-] Could not save session:", err)
+	}
+}
+
+// tokenEmail asks tokeninfo for the email of the token owner
+func tokenEmail(token string) string {
+	resp, err := http.Get(fmt.Sprintf("https://oauth2.googleapis.com/tokeninfo?access_token=%s", token))
+	if err != nil {
+		return ""
+	}
+	defer resp.Body.Close()
+
+	var info struct {
+		Email string `json:"email"`
+	}
+	if resp.StatusCode != http.StatusOK || json.NewDecoder(resp.Body).Decode(&info) != nil {
+		return ""
 	}
+	return info.Email
 }
 
 func TokenImpersonate(saEmail, token, projectID string) *string {
@@ -5,6 +5,7 @@ import (
 	"os"
 
 	azure "github.com/f0rk3b0mb/GoCloudGhost/azure"
+	"github.com/f0rk3b0mb/GoCloudGhost/export"
 	gcp "github.com/f0rk3b0mb/GoCloudGhost/gcp"
 	"github.com/spf13/cobra"
 )
@@ -19,6 +20,7 @@ func init() {
 	// Register blob command and its subcommands
 	rootCmd.AddCommand(azure.AzureCmd)
 	rootCmd.AddCommand(gcp.GcpCmd)
+	rootCmd.AddCommand(export.ExportCmd)
 }
 
 func main() {
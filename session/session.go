@@ -0,0 +1,50 @@
+// Package session persists enumeration results between runs so that later
+// commands, such as exports and attack path analysis, can reuse them.
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Dir is the directory, relative to the working directory, where session data is stored
+const Dir = ".gocloudghost"
+
+// Save stores v as JSON under the given name, replacing any previous value
+func Save(name string, v interface{}) error {
+	if err := os.MkdirAll(Dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create session directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode session data %s: %w", name, err)
+	}
+
+	return os.WriteFile(path(name), data, 0o600)
+}
+
+// Load decodes the value stored under name into v. It returns false without
+// error when nothing has been saved under that name yet.
+func Load(name string, v interface{}) (bool, error) {
+	data, err := os.ReadFile(path(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read session data %s: %w", name, err)
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return false, fmt.Errorf("failed to decode session data %s: %w", name, err)
+	}
+
+	return true, nil
+}
+
+func path(name string) string {
+	return filepath.Join(Dir, name+".json")
+}